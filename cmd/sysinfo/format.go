@@ -0,0 +1,218 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+	FormatYAML  = "yaml"
+)
+
+type Table struct {
+	Header []string
+	Rows   [][]string
+}
+
+func NewTable(header ...string) *Table {
+	return &Table{
+		Header: header,
+		Rows:   make([][]string, 0),
+	}
+}
+
+func (t *Table) Append(row ...string) {
+	t.Rows = append(t.Rows, row)
+}
+
+func (t *Table) Write(w io.Writer) error {
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(writer, strings.Join(t.Header, "\t")); nil != err {
+		return err
+	}
+	for _, row := range t.Rows {
+		if _, err := fmt.Fprintln(writer, strings.Join(row, "\t")); nil != err {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
+// FormatBytes prints a size with binary units, such as "1.5 GiB".
+func FormatBytes(value float64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	sign := ""
+	if value < 0 {
+		sign, value = "-", -value
+	}
+	unit := 0
+	for value >= 1024 && unit < len(units)-1 {
+		value = value / 1024
+		unit = unit + 1
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%s%.0f %s", sign, value, units[unit])
+	}
+	return fmt.Sprintf("%s%.1f %s", sign, value, units[unit])
+}
+
+// FormatChange prints the difference between two sizes, signed.
+func FormatChange(previous, current int64) string {
+	delta := current - previous
+	if delta > 0 {
+		return "+" + FormatBytes(float64(delta))
+	}
+	return FormatBytes(float64(delta))
+}
+
+func FormatPercent(value float64) string {
+	return strconv.FormatFloat(value, 'f', 1, 64)
+}
+
+// FormatFloat prints rates per second and durations in milliseconds.
+func FormatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
+
+func FormatCount(value int64) string {
+	return strconv.FormatInt(value, 10)
+}
+
+var (
+	_PlainPattern  = regexp.MustCompile(`^[A-Za-z_/][A-Za-z0-9_./@+-]*$`)
+	_ReservedWords = map[string]bool{
+		"true": true, "false": true, "yes": true, "no": true, "on": true,
+		"off": true, "y": true, "n": true, "null": true, "~": true,
+	}
+)
+
+// _YAMLString leaves strings that cannot be read as anything else plain and
+// double quotes the rest, whose JSON escapes YAML understands.
+func _YAMLString(value string) string {
+	if _PlainPattern.MatchString(value) && !_ReservedWords[strings.ToLower(value)] {
+		return value
+	}
+	return strconv.Quote(value)
+}
+
+const (
+	_ScalarNode = iota
+	_ObjectNode
+	_ArrayNode
+)
+
+// _Node keeps JSON objects in document order, which decoding into a map
+// would lose.
+type _Node struct {
+	kind     int
+	scalar   string
+	keys     []string
+	children []*_Node
+}
+
+func _DecodeNode(decoder *json.Decoder) (*_Node, error) {
+	token, err := decoder.Token()
+	if nil != err {
+		return nil, err
+	}
+	switch v := token.(type) {
+	case json.Delim:
+		node := &_Node{kind: _ObjectNode}
+		if v == '[' {
+			node.kind = _ArrayNode
+		}
+		for decoder.More() {
+			if node.kind == _ObjectNode {
+				key, err := decoder.Token()
+				if nil != err {
+					return nil, err
+				}
+				node.keys = append(node.keys, _YAMLString(key.(string)))
+			}
+			child, err := _DecodeNode(decoder)
+			if nil != err {
+				return nil, err
+			}
+			node.children = append(node.children, child)
+		}
+		// Consume the closing delimiter.
+		if _, err := decoder.Token(); nil != err {
+			return nil, err
+		}
+		return node, nil
+	case string:
+		return &_Node{kind: _ScalarNode, scalar: _YAMLString(v)}, nil
+	case json.Number:
+		return &_Node{kind: _ScalarNode, scalar: v.String()}, nil
+	case bool:
+		return &_Node{kind: _ScalarNode, scalar: strconv.FormatBool(v)}, nil
+	default:
+		return &_Node{kind: _ScalarNode, scalar: "null"}, nil
+	}
+}
+
+// _Inline tells whether the node fits on the line of its key or dash.
+func (n *_Node) _Inline() (string, bool) {
+	switch {
+	case n.kind == _ScalarNode:
+		return n.scalar, true
+	case n.kind == _ObjectNode && len(n.children) == 0:
+		return "{}", true
+	case n.kind == _ArrayNode && len(n.children) == 0:
+		return "[]", true
+	default:
+		return "", false
+	}
+}
+
+// _Write writes the node in block style. The first line starts with first,
+// which carries the "- " of a sequence entry the node is the value of.
+func (n *_Node) _Write(builder *strings.Builder, indent int, first string) {
+	pad := strings.Repeat(" ", indent)
+	if value, ok := n._Inline(); ok {
+		builder.WriteString(first + value + "\n")
+		return
+	}
+	for i, child := range n.children {
+		prefix := pad
+		if i == 0 {
+			prefix = first
+		}
+		if n.kind == _ObjectNode {
+			if value, ok := child._Inline(); ok {
+				builder.WriteString(prefix + n.keys[i] + ": " + value + "\n")
+			} else {
+				builder.WriteString(prefix + n.keys[i] + ":\n")
+				child._Write(builder, indent+2, pad+"  ")
+			}
+			continue
+		}
+		child._Write(builder, indent+2, prefix+"- ")
+	}
+}
+
+// WriteYAML writes v as a YAML document, going through its JSON encoding so
+// that the json struct tags name the keys.
+func WriteYAML(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if nil != err {
+		return err
+	}
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	node, err := _DecodeNode(decoder)
+	if nil != err {
+		return err
+	}
+	builder := new(strings.Builder)
+	node._Write(builder, 0, "")
+	_, err = io.WriteString(w, builder.String())
+	return err
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	sysinfo "github.com/thebagchi/sysinfo-go"
+)
+
+func TestWriteYAML(t *testing.T) {
+	value := map[string]interface{}{
+		"name":    "eth0",
+		"version": "1.0",
+		"empty":   []string{},
+		"items": []interface{}{
+			map[string]interface{}{"a": 1, "b": "x: y"},
+			[]int{1, 2},
+		},
+	}
+	builder := new(strings.Builder)
+	if err := WriteYAML(builder, value); nil != err {
+		t.Fatal(err)
+	}
+	expected := "empty: []\n" +
+		"items:\n" +
+		"  - a: 1\n" +
+		"    b: \"x: y\"\n" +
+		"  - - 1\n" +
+		"    - 2\n" +
+		"name: eth0\n" +
+		"version: \"1.0\"\n"
+	if builder.String() != expected {
+		t.Errorf("unexpected yaml:\n%s", builder.String())
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	for value, expected := range map[float64]string{
+		0:             "0 B",
+		1023:          "1023 B",
+		1536:          "1.5 KiB",
+		-1 << 30:      "-1.0 GiB",
+		5 * (1 << 40): "5.0 TiB",
+	} {
+		if actual := FormatBytes(value); actual != expected {
+			t.Errorf("FormatBytes(%v) = %q, expected %q", value, actual, expected)
+		}
+	}
+	if actual := FormatFloat(1234.5); actual != "1234.50" {
+		t.Errorf("FormatFloat(1234.5) = %q", actual)
+	}
+}
+
+func TestParseArguments(t *testing.T) {
+	options, names, err := ParseArguments([]string{"-o", "json", "cpu", "-watch", "2s", "all"}, ioutil.Discard)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if options.Format != FormatJSON || options.Watch.Seconds() != 2 || len(names) != 1+len(All) || names[0] != "cpu" {
+		t.Errorf("unexpected options %+v and commands %v", options, names)
+	}
+	if _, _, err := ParseArguments([]string{"bogus"}, ioutil.Discard); nil == err {
+		t.Error("expected error for unknown command")
+	}
+}
+
+func TestTopRender(t *testing.T) {
+	if _, _, err := ParseArguments([]string{"top", "cpu"}, ioutil.Discard); nil == err {
+		t.Error("expected error for top combined with another command")
+	}
+	frame := &TopFrame{
+		At:     time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		Uptime: 26 * time.Hour,
+		Load:   &sysinfo.Load{Load1: 1.5},
+		CPU: &sysinfo.CPUUsage{CPUs: []sysinfo.CPUUtilisation{
+			{CPUId: "cpu0", Usage: 50},
+			{CPUId: "cpu1", Usage: 100},
+		}},
+		Memory: &sysinfo.MemInfo{Total: 1024, Available: 256},
+		Processes: []ProcessReport{
+			{Process: sysinfo.Process{Pid: 2, Name: "b", ResidentSize: 512}, CPU: 1},
+			{Process: sysinfo.Process{Pid: 1, Name: "a", ResidentSize: 128}, CPU: 2},
+		},
+		Sort: SortMemory,
+	}
+	SortProcesses(frame.Processes, frame.Sort)
+	if frame.Processes[0].Pid != 2 {
+		t.Errorf("unexpected order %+v", frame.Processes)
+	}
+	lines := frame.Render(80, 20)
+	if len(lines) != 20 {
+		t.Fatalf("expected 20 lines, got %d", len(lines))
+	}
+	if !strings.Contains(lines[0], "up 1 day, 2:00") || !strings.Contains(lines[1], "0     [") {
+		t.Errorf("unexpected header %q %q", lines[0], lines[1])
+	}
+	if bar := Bar(50, 12); bar != "[|||||     ]" {
+		t.Errorf("unexpected bar %q", bar)
+	}
+	// Styled headers must fit narrow terminals as well.
+	unstyled := strings.NewReplacer(EscapeBold, "", EscapeReverse, "", EscapeReset, "")
+	for _, width := range []int{80, 30} {
+		for _, line := range frame.Render(width, 20) {
+			if visible := unstyled.Replace(line); len(visible) != width || strings.Contains(visible, "\x1b") {
+				t.Errorf("line %q is not %d columns", line, width)
+			}
+		}
+	}
+}
+
+func TestWatchSchema(t *testing.T) {
+	fsys := fstest.MapFS{
+		"proc/diskstats": &fstest.MapFile{Data: []byte("   8       0 sda 10 0 8 1500 0 0 0 0 0 0 0\n")},
+		"proc/net/dev":   &fstest.MapFile{Data: []byte("Inter-|   Receive                                                |  Transmit\n face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop colls carrier compressed\n  eth0: 100 1 0 0 0 0 0 0 200 2 0 0 0 0 0 0\n")},
+	}
+	var (
+		session = NewSession(sysinfo.NewCollector(fsys, "proc", "sys"))
+		options = &Options{Format: FormatJSON, Watch: time.Millisecond}
+		records = make([]string, 0, 2)
+	)
+	for i := 0; i < 2; i++ {
+		sections, err := Collect(session, []string{"disk", "net"})
+		if nil != err {
+			t.Fatal(err)
+		}
+		var buffer strings.Builder
+		if err := Write(&buffer, options, sections, time.Now()); nil != err {
+			t.Fatal(err)
+		}
+		records = append(records, buffer.String())
+		time.Sleep(options.Watch)
+	}
+	for i, record := range records {
+		var value struct {
+			Disk map[string]json.RawMessage `json:"disk"`
+			Net  map[string]json.RawMessage `json:"net"`
+		}
+		if err := json.Unmarshal([]byte(record), &value); nil != err {
+			t.Fatalf("record %d: %v", i, err)
+		}
+		for _, report := range []map[string]json.RawMessage{value.Disk, value.Net} {
+			if len(report) != 2 || nil == report["stats"] || nil == report["rates"] {
+				t.Errorf("unexpected schema of record %d: %s", i, record)
+			}
+		}
+	}
+	if !strings.Contains(records[0], `"rates":[]`) || strings.Contains(records[1], `"rates":[]`) {
+		t.Errorf("unexpected rates in %q", records)
+	}
+}
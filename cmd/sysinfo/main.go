@@ -0,0 +1,217 @@
+// Command sysinfo prints what the sysinfo-go collectors report about the
+// host as tables, JSON or YAML, once or at an interval.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	sysinfo "github.com/thebagchi/sysinfo-go"
+)
+
+const usage = `usage: sysinfo [flags] <command> [flags]
+
+commands:
+  cpu     CPU models and utilisation
+  mem     memory and swap
+  disk    disk I/O
+  net     network interface traffic
+  fs      filesystem usage
+  load    load averages
+  uname   kernel and host name
+  procs   processes
+  all     everything above
+  top     refreshing full screen view, on its own
+
+flags:
+`
+
+type Command func(session *Session) (*Section, error)
+
+var Commands = map[string]Command{
+	"cpu":   (*Session).CPU,
+	"mem":   (*Session).Memory,
+	"disk":  (*Session).Disk,
+	"net":   (*Session).Network,
+	"fs":    (*Session).FileSystems,
+	"load":  (*Session).Load,
+	"uname": (*Session).UName,
+	"procs": (*Session).Processes,
+}
+
+// All lists the commands run by "all", in output order.
+var All = []string{"uname", "load", "cpu", "mem", "disk", "net", "fs", "procs"}
+
+type Options struct {
+	Format string
+	Watch  time.Duration
+	Count  int
+	Proc   string
+	Sys    string
+	Sort   string
+}
+
+// ParseArguments accepts flags both before and after the command.
+func ParseArguments(args []string, output io.Writer) (*Options, []string, error) {
+	var (
+		options = new(Options)
+		flags   = flag.NewFlagSet("sysinfo", flag.ContinueOnError)
+	)
+	flags.SetOutput(output)
+	flags.StringVar(&options.Format, "output", FormatTable, "output format: table, json or yaml")
+	flags.StringVar(&options.Format, "o", FormatTable, "shorthand for -output")
+	flags.DurationVar(&options.Watch, "watch", 0, "repeat at this interval, showing deltas, until interrupted")
+	flags.IntVar(&options.Count, "count", 0, "stop watching after this many collections, 0 for no limit")
+	flags.StringVar(&options.Proc, "proc", sysinfo.ProcDirectory, "procfs root")
+	flags.StringVar(&options.Sys, "sys", sysinfo.SysDirectory, "sysfs root")
+	flags.StringVar(&options.Sort, "sort", SortCPU, "top process order: cpu, mem, pid or name")
+	flags.Usage = func() {
+		fmt.Fprint(output, usage)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); nil != err {
+		return nil, nil, err
+	}
+	commands := make([]string, 0)
+	for rest := flags.Args(); len(rest) > 0; rest = flags.Args() {
+		commands = append(commands, rest[0])
+		if err := flags.Parse(rest[1:]); nil != err {
+			return nil, nil, err
+		}
+	}
+	if len(commands) == 0 {
+		flags.Usage()
+		return nil, nil, errors.New("no command given")
+	}
+	switch options.Format {
+	case FormatTable, FormatJSON, FormatYAML:
+		// Do Nothing
+	default:
+		return nil, nil, fmt.Errorf("unknown output format %q", options.Format)
+	}
+	switch options.Sort {
+	case SortCPU, SortMemory, SortPid, SortName:
+		// Do Nothing
+	default:
+		return nil, nil, fmt.Errorf("unknown sort order %q", options.Sort)
+	}
+	names := make([]string, 0)
+	for _, command := range commands {
+		if command == TopCommand {
+			if len(commands) > 1 {
+				return nil, nil, errors.New("top cannot be combined with other commands")
+			}
+			names = append(names, command)
+			continue
+		}
+		if command == "all" {
+			names = append(names, All...)
+			continue
+		}
+		if _, ok := Commands[command]; !ok {
+			return nil, nil, fmt.Errorf("unknown command %q", command)
+		}
+		names = append(names, command)
+	}
+	return options, names, nil
+}
+
+// Collect runs the commands, failing on the first error.
+func Collect(session *Session, names []string) (Sections, error) {
+	sections := make(Sections, 0, len(names))
+	for _, name := range names {
+		section, err := Commands[name](session)
+		if nil != err {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		sections = append(sections, *section)
+	}
+	return sections, nil
+}
+
+// Write prints the sections. A single section is printed on its own, more
+// are printed under their names. Watch mode prints JSON one line per
+// collection and YAML one document per collection.
+func Write(w io.Writer, options *Options, sections Sections, at time.Time) error {
+	var value interface{} = sections
+	if len(sections) == 1 {
+		value = sections[0].Value
+	}
+	switch options.Format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		if options.Watch == 0 {
+			encoder.SetIndent("", "  ")
+		}
+		return encoder.Encode(value)
+	case FormatYAML:
+		if options.Watch > 0 {
+			if _, err := fmt.Fprintln(w, "---"); nil != err {
+				return err
+			}
+		}
+		return WriteYAML(w, value)
+	default:
+		if options.Watch > 0 {
+			if _, err := fmt.Fprintf(w, "%s\n", at.Format("2006-01-02 15:04:05")); nil != err {
+				return err
+			}
+		}
+		for i, section := range sections {
+			if len(sections) > 1 {
+				if _, err := fmt.Fprintln(w, strings.ToUpper(section.Name)); nil != err {
+					return err
+				}
+			}
+			if err := section.Table.Write(w); nil != err {
+				return err
+			}
+			if i < len(sections)-1 || options.Watch > 0 {
+				if _, err := fmt.Fprintln(w); nil != err {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+}
+
+func Run(args []string, stdout, stderr io.Writer) error {
+	options, names, err := ParseArguments(args, stderr)
+	if nil != err {
+		return err
+	}
+	session := NewSession(sysinfo.NewCollector(nil, options.Proc, options.Sys))
+	if names[0] == TopCommand {
+		return RunTop(session, options, stdout)
+	}
+	for count := 1; ; count++ {
+		sections, err := Collect(session, names)
+		if nil != err {
+			return err
+		}
+		if err := Write(stdout, options, sections, time.Now()); nil != err {
+			return err
+		}
+		if options.Watch <= 0 || (options.Count > 0 && count >= options.Count) {
+			return nil
+		}
+		time.Sleep(options.Watch)
+	}
+}
+
+func main() {
+	if err := Run(os.Args[1:], os.Stdout, os.Stderr); nil != err {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		fmt.Fprintln(os.Stderr, "sysinfo:", err)
+		os.Exit(2)
+	}
+}
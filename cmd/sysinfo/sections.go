@@ -0,0 +1,342 @@
+package main
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	sysinfo "github.com/thebagchi/sysinfo-go"
+)
+
+// Section is the output of one command: Value is marshalled for JSON and
+// YAML output and Table printed otherwise.
+type Section struct {
+	Name  string
+	Value interface{}
+	Table *Table
+}
+
+type Sections []Section
+
+// MarshalJSON writes the sections as one object keyed by name, in order.
+func (s Sections) MarshalJSON() ([]byte, error) {
+	builder := new(strings.Builder)
+	builder.WriteString("{")
+	for i, section := range s {
+		if i > 0 {
+			builder.WriteString(",")
+		}
+		data, err := json.Marshal(section.Value)
+		if nil != err {
+			return nil, err
+		}
+		builder.WriteString(strconv.Quote(section.Name) + ":")
+		builder.Write(data)
+	}
+	builder.WriteString("}")
+	return []byte(builder.String()), nil
+}
+
+type CPUReport struct {
+	Processors []sysinfo.ProcessorInformation `json:"processors"`
+	Usage      *sysinfo.CPUUsage              `json:"usage"`
+}
+
+// DiskReport and NetworkReport carry both the totals and the rates, so every
+// record of watch mode has the same schema. Rates are empty on the first
+// collection.
+type DiskReport struct {
+	Stats sysinfo.DiskStats `json:"stats"`
+	Rates sysinfo.DiskRates `json:"rates"`
+}
+
+type NetworkReport struct {
+	Stats sysinfo.NetworkStats `json:"stats"`
+	Rates sysinfo.NetworkRates `json:"rates"`
+}
+
+type ProcessReport struct {
+	sysinfo.Process
+	CPU float64 `json:"cpu"`
+}
+
+// Session keeps the samplers and previous snapshots that successive
+// collections in watch mode report deltas against.
+type Session struct {
+	collector *sysinfo.Collector
+	cpu       *sysinfo.CPUSampler
+	disk      *sysinfo.DiskRateSampler
+	network   *sysinfo.NetworkRateSampler
+	memory    *sysinfo.MemInfo
+	times     map[int]float64
+	time      time.Time
+}
+
+func NewSession(collector *sysinfo.Collector) *Session {
+	return &Session{
+		collector: collector,
+		cpu:       sysinfo.NewCPUSampler(collector),
+		disk:      sysinfo.NewDiskRateSampler(collector),
+		network:   sysinfo.NewNetworkRateSampler(collector),
+	}
+}
+
+func (s *Session) CPU() (*Section, error) {
+	info, err := s.collector.GetCPUInfo()
+	if nil != err {
+		return nil, err
+	}
+	usage, err := s.cpu.Sample()
+	if nil != err {
+		return nil, err
+	}
+	models := make(map[string]sysinfo.ProcessorInformation)
+	for _, processor := range info.Processors {
+		models[sysinfo.StatCPU+strconv.FormatInt(processor.Id, 10)] = processor
+	}
+	table := NewTable("CPU", "MODEL", "MHZ", "USER%", "SYSTEM%", "IOWAIT%", "STEAL%", "IDLE%", "USAGE%")
+	for _, cpu := range append(usage.CPUs, usage.Total) {
+		processor := models[cpu.CPUId]
+		table.Append(
+			cpu.CPUId,
+			processor.ModelName,
+			processor.CPUFrequency,
+			FormatPercent(cpu.User+cpu.Nice),
+			FormatPercent(cpu.System+cpu.IRQ+cpu.SoftIRQ),
+			FormatPercent(cpu.IOWait),
+			FormatPercent(cpu.Steal),
+			FormatPercent(cpu.Idle),
+			FormatPercent(cpu.Usage),
+		)
+	}
+	return &Section{
+		Name:  "cpu",
+		Value: &CPUReport{Processors: info.Processors, Usage: usage},
+		Table: table,
+	}, nil
+}
+
+// Memory shows how each value changed since the previous collection in
+// watch mode.
+func (s *Session) Memory() (*Section, error) {
+	mem, err := s.collector.GetMemInfo()
+	if nil != err {
+		return nil, err
+	}
+	previous := s.memory
+	s.memory = mem
+	table := NewTable("MEMORY", "VALUE")
+	if nil != previous {
+		table = NewTable("MEMORY", "VALUE", "CHANGE")
+	}
+	for _, it := range []struct {
+		name string
+		get  func(mem *sysinfo.MemInfo) int64
+	}{
+		{"Total", func(mem *sysinfo.MemInfo) int64 { return mem.Total }},
+		{"Used", func(mem *sysinfo.MemInfo) int64 { return mem.Total - mem.Available }},
+		{"Available", func(mem *sysinfo.MemInfo) int64 { return mem.Available }},
+		{"Free", func(mem *sysinfo.MemInfo) int64 { return mem.Free }},
+		{"Buffers", func(mem *sysinfo.MemInfo) int64 { return mem.Buffered }},
+		{"Cached", func(mem *sysinfo.MemInfo) int64 { return mem.Cached }},
+		{"Shared", func(mem *sysinfo.MemInfo) int64 { return mem.Shmem }},
+		{"Dirty", func(mem *sysinfo.MemInfo) int64 { return mem.Dirty }},
+		{"Swap Total", func(mem *sysinfo.MemInfo) int64 { return mem.SwapTotal }},
+		{"Swap Used", func(mem *sysinfo.MemInfo) int64 { return mem.SwapTotal - mem.SwapFree }},
+		{"Swap Free", func(mem *sysinfo.MemInfo) int64 { return mem.SwapFree }},
+	} {
+		row := []string{it.name, FormatBytes(float64(it.get(mem)))}
+		if nil != previous {
+			row = append(row, FormatChange(it.get(previous), it.get(mem)))
+		}
+		table.Append(row...)
+	}
+	return &Section{Name: "mem", Value: mem, Table: table}, nil
+}
+
+// Disk tabulates the totals since boot on the first collection and the rates
+// since the previous one afterwards, like iostat.
+func (s *Session) Disk() (*Section, error) {
+	stats, err := s.collector.GetDiskStats()
+	if nil != err {
+		return nil, err
+	}
+	report := &DiskReport{Stats: stats, Rates: s.disk.Update(stats, time.Now())}
+	if nil == report.Rates {
+		report.Rates = sysinfo.DiskRates{}
+	}
+	if rates := report.Rates; len(rates) > 0 || len(stats) == 0 {
+		table := NewTable("DEVICE", "READS/S", "WRITES/S", "READ/S", "WRITTEN/S", "AWAIT", "UTIL%")
+		for _, rate := range rates {
+			table.Append(
+				rate.Device,
+				FormatFloat(rate.ReadsPerSecond),
+				FormatFloat(rate.WritesPerSecond),
+				FormatBytes(rate.ReadBytes),
+				FormatBytes(rate.WriteBytes),
+				FormatFloat(rate.Await),
+				FormatPercent(rate.Utilisation),
+			)
+		}
+		return &Section{Name: "disk", Value: report, Table: table}, nil
+	}
+	table := NewTable("DEVICE", "READS", "WRITES", "READ", "WRITTEN", "IO TIME")
+	for _, stat := range stats {
+		table.Append(
+			stat.Device,
+			FormatCount(stat.ReadsComplete),
+			FormatCount(stat.WritesComplete),
+			FormatBytes(float64(stat.SectorsRead*sysinfo.DiskStatSectorSize)),
+			FormatBytes(float64(stat.SectorsWritten*sysinfo.DiskStatSectorSize)),
+			(time.Duration(stat.TotalIOTime) * time.Millisecond).String(),
+		)
+	}
+	return &Section{Name: "disk", Value: report, Table: table}, nil
+}
+
+// Network tabulates the totals since boot on the first collection and the rates
+// since the previous one afterwards.
+func (s *Session) Network() (*Section, error) {
+	stats, err := s.collector.GetNetworkStats()
+	if nil != err {
+		return nil, err
+	}
+	report := &NetworkReport{Stats: stats, Rates: s.network.Update(stats, time.Now())}
+	if nil == report.Rates {
+		report.Rates = sysinfo.NetworkRates{}
+	}
+	if rates := report.Rates; len(rates) > 0 || len(stats) == 0 {
+		table := NewTable("INTERFACE", "RX/S", "TX/S", "RX PKTS/S", "TX PKTS/S", "ERRORS/S", "DROPS/S")
+		for _, rate := range rates {
+			table.Append(
+				rate.Interface,
+				FormatBytes(rate.ReceivedBytes),
+				FormatBytes(rate.TransmittedBytes),
+				FormatFloat(rate.ReceivedPackets),
+				FormatFloat(rate.TransmittedPackets),
+				FormatFloat(rate.ReceivedErrors+rate.TransmittedErrors),
+				FormatFloat(rate.ReceivedDropped+rate.TransmittedDropped),
+			)
+		}
+		return &Section{Name: "net", Value: report, Table: table}, nil
+	}
+	table := NewTable("INTERFACE", "RX", "TX", "RX PKTS", "TX PKTS", "ERRORS", "DROPS")
+	for _, stat := range stats {
+		table.Append(
+			stat.Interface,
+			FormatBytes(float64(stat.ReceivedBytes)),
+			FormatBytes(float64(stat.TransmittedBytes)),
+			FormatCount(stat.ReceivedPackets),
+			FormatCount(stat.TransmittedPackets),
+			FormatCount(stat.ReceivedErrors+stat.TransmittedErrors),
+			FormatCount(stat.ReceivedDropped+stat.TransmittedDropped),
+		)
+	}
+	return &Section{Name: "net", Value: report, Table: table}, nil
+}
+
+func (s *Session) FileSystems() (*Section, error) {
+	usage, err := s.collector.GetFileSystemUsage()
+	if nil != err {
+		return nil, err
+	}
+	table := NewTable("FILESYSTEM", "TYPE", "SIZE", "USED", "AVAIL", "USE%", "MOUNTED ON")
+	for _, it := range usage {
+		table.Append(
+			it.Mount.Source,
+			it.Mount.FileSystemType,
+			FormatBytes(float64(it.Stat.Capacity)),
+			FormatBytes(float64(it.Stat.Used)),
+			FormatBytes(float64(it.Stat.Available)),
+			FormatPercent(it.Stat.UsedPercent),
+			it.Mount.MountPoint,
+		)
+	}
+	return &Section{Name: "fs", Value: usage, Table: table}, nil
+}
+
+func (s *Session) Load() (*Section, error) {
+	load, err := s.collector.GetLoadAvg()
+	if nil != err {
+		return nil, err
+	}
+	table := NewTable("LOAD1", "LOAD5", "LOAD15", "RUNNABLE", "ENTITIES")
+	table.Append(
+		strconv.FormatFloat(load.Load1, 'f', 2, 64),
+		strconv.FormatFloat(load.Load5, 'f', 2, 64),
+		strconv.FormatFloat(load.Load15, 'f', 2, 64),
+		FormatCount(load.Runnable),
+		FormatCount(load.Entities),
+	)
+	return &Section{Name: "load", Value: load, Table: table}, nil
+}
+
+func (s *Session) UName() (*Section, error) {
+	uname, err := sysinfo.GetUName()
+	if nil != err {
+		return nil, err
+	}
+	table := NewTable("KERNEL", "VALUE")
+	table.Append("System", uname.SysName)
+	table.Append("Node", uname.NodeName)
+	table.Append("Release", uname.Release)
+	table.Append("Version", uname.Version)
+	table.Append("Machine", uname.Machine)
+	table.Append("Domain", uname.DomainName)
+	return &Section{Name: "uname", Value: uname, Table: table}, nil
+}
+
+// ProcessReports returns the processes with their CPU usage in percent of
+// one CPU, averaged since they started on the first collection and since the
+// previous collection afterwards.
+func (s *Session) ProcessReports() ([]ProcessReport, error) {
+	processes, err := s.collector.GetProcesses()
+	if nil != err {
+		return nil, err
+	}
+	var (
+		now      = time.Now()
+		previous = s.times
+		elapsed  = now.Sub(s.time).Seconds()
+		reports  = make([]ProcessReport, 0, len(processes))
+	)
+	s.times, s.time = make(map[int]float64, len(processes)), now
+	for _, process := range processes {
+		total := process.UserTime + process.SystemTime
+		s.times[process.Pid] = total
+		report := ProcessReport{Process: process}
+		if last, ok := previous[process.Pid]; ok && elapsed > 0 {
+			report.CPU = (total - last) / elapsed * 100
+		} else if age := now.Sub(process.StartTime).Seconds(); age > 0 {
+			report.CPU = total / age * 100
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+func (s *Session) Processes() (*Section, error) {
+	reports, err := s.ProcessReports()
+	if nil != err {
+		return nil, err
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].Pid < reports[j].Pid
+	})
+	table := NewTable("PID", "PPID", "UID", "STATE", "THREADS", "RSS", "CPU%", "COMMAND")
+	for _, report := range reports {
+		table.Append(
+			strconv.Itoa(report.Pid),
+			strconv.Itoa(report.ParentPid),
+			FormatCount(report.Uid),
+			report.State,
+			FormatCount(report.Threads),
+			FormatBytes(float64(report.ResidentSize)),
+			FormatPercent(report.CPU),
+			report.Name,
+		)
+	}
+	return &Section{Name: "procs", Value: reports, Table: table}, nil
+}
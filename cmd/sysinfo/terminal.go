@@ -0,0 +1,60 @@
+package main
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+const (
+	EscapeHome            = "\x1b[H"
+	EscapeClearLine       = "\x1b[K"
+	EscapeClearBelow      = "\x1b[J"
+	EscapeReverse         = "\x1b[7m"
+	EscapeBold            = "\x1b[1m"
+	EscapeReset           = "\x1b[0m"
+	EscapeAlternateScreen = "\x1b[?1049h"
+	EscapeMainScreen      = "\x1b[?1049l"
+	EscapeHideCursor      = "\x1b[?25l"
+	EscapeShowCursor      = "\x1b[?25h"
+)
+
+func _IOCtl(fd, request uintptr, argument unsafe.Pointer) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, request, uintptr(argument)); errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// TerminalSize returns the columns and rows of the terminal at fd.
+func TerminalSize(fd uintptr) (int, int, error) {
+	var size struct {
+		rows    uint16
+		columns uint16
+		x       uint16
+		y       uint16
+	}
+	if err := _IOCtl(fd, syscall.TIOCGWINSZ, unsafe.Pointer(&size)); nil != err {
+		return 0, 0, err
+	}
+	return int(size.columns), int(size.rows), nil
+}
+
+// MakeRaw turns off line buffering and echo on the terminal at fd, so
+// single key presses can be read, and returns a function restoring the
+// previous state. Signals such as Ctrl-C keep working.
+func MakeRaw(fd uintptr) (func() error, error) {
+	var previous syscall.Termios
+	if err := _IOCtl(fd, syscall.TCGETS, unsafe.Pointer(&previous)); nil != err {
+		return nil, err
+	}
+	raw := previous
+	raw.Lflag &^= syscall.ICANON | syscall.ECHO
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+	if err := _IOCtl(fd, syscall.TCSETS, unsafe.Pointer(&raw)); nil != err {
+		return nil, err
+	}
+	return func() error {
+		return _IOCtl(fd, syscall.TCSETS, unsafe.Pointer(&previous))
+	}, nil
+}
@@ -0,0 +1,359 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	sysinfo "github.com/thebagchi/sysinfo-go"
+)
+
+const TopCommand = "top"
+
+const (
+	SortCPU    = "cpu"
+	SortMemory = "mem"
+	SortPid    = "pid"
+	SortName   = "name"
+)
+
+// TopKeys maps the keys pressed in top onto process list orders.
+var TopKeys = map[byte]string{
+	'c': SortCPU,
+	'm': SortMemory,
+	'p': SortPid,
+	'n': SortName,
+}
+
+const (
+	DefaultTopInterval = 2 * time.Second
+	// _TopPrime is how long the first frame waits for rates to be computed.
+	_TopPrime   = 250 * time.Millisecond
+	_TopDevices = 4
+)
+
+// TopFrame is everything one refresh of top shows.
+type TopFrame struct {
+	At        time.Time
+	Uptime    time.Duration
+	Load      *sysinfo.Load
+	CPU       *sysinfo.CPUUsage
+	Memory    *sysinfo.MemInfo
+	Network   sysinfo.NetworkRates
+	Disks     sysinfo.DiskRates
+	Processes []ProcessReport
+	Sort      string
+}
+
+// SortProcesses orders processes by the given key, busiest and largest
+// first.
+func SortProcesses(processes []ProcessReport, key string) {
+	sort.SliceStable(processes, func(i, j int) bool {
+		a, b := &processes[i], &processes[j]
+		switch key {
+		case SortMemory:
+			if a.ResidentSize != b.ResidentSize {
+				return a.ResidentSize > b.ResidentSize
+			}
+		case SortName:
+			if a.Name != b.Name {
+				return a.Name < b.Name
+			}
+		case SortPid:
+			// Fall through to the pid order below.
+		default:
+			if a.CPU != b.CPU {
+				return a.CPU > b.CPU
+			}
+		}
+		return a.Pid < b.Pid
+	})
+}
+
+// Bar draws a meter such as "[|||||     ]" filled to percent.
+func Bar(percent float64, width int) string {
+	if width < 3 {
+		return ""
+	}
+	inner := width - 2
+	filled := int(percent/100*float64(inner) + 0.5)
+	if filled < 0 {
+		filled = 0
+	}
+	if filled > inner {
+		filled = inner
+	}
+	return "[" + strings.Repeat("|", filled) + strings.Repeat(" ", inner-filled) + "]"
+}
+
+// _Fit cuts a line to the terminal width and pads it to fill it.
+func _Fit(line string, width int) string {
+	if utf8.RuneCountInString(line) > width {
+		runes := []rune(line)
+		return string(runes[:width])
+	}
+	return line + strings.Repeat(" ", width-utf8.RuneCountInString(line))
+}
+
+func _FormatUptime(uptime time.Duration) string {
+	var (
+		days    = int(uptime.Hours()) / 24
+		hours   = int(uptime.Hours()) % 24
+		minutes = int(uptime.Minutes()) % 60
+	)
+	if days == 1 {
+		return fmt.Sprintf("1 day, %d:%02d", hours, minutes)
+	}
+	if days > 1 {
+		return fmt.Sprintf("%d days, %d:%02d", days, hours, minutes)
+	}
+	return fmt.Sprintf("%d:%02d", hours, minutes)
+}
+
+// Render lays the frame out as lines of exactly width columns, giving the
+// process list whatever height is left.
+func (f *TopFrame) Render(width, height int) []string {
+	var (
+		lines  = make([]string, 0, height)
+		styles = make(map[int]string)
+	)
+	header := fmt.Sprintf("sysinfo top - %s up %s", f.At.Format("15:04:05"), _FormatUptime(f.Uptime))
+	if nil != f.Load {
+		header += fmt.Sprintf(", load %.2f %.2f %.2f, tasks %d", f.Load.Load1, f.Load.Load5, f.Load.Load15, len(f.Processes))
+	}
+	lines = append(lines, header)
+
+	if nil != f.CPU {
+		var (
+			columns = width / 40
+			cell    = width
+			row     = ""
+		)
+		if columns < 1 {
+			columns = 1
+		}
+		if len(f.CPU.CPUs) < columns {
+			columns = len(f.CPU.CPUs)
+		}
+		if columns > 0 {
+			cell = width / columns
+		}
+		for i, cpu := range f.CPU.CPUs {
+			label := fmt.Sprintf("%-6s", strings.TrimPrefix(cpu.CPUId, sysinfo.StatCPU))
+			value := fmt.Sprintf(" %5.1f%%", cpu.Usage)
+			row += _Fit(label+Bar(cpu.Usage, cell-len(label)-len(value)-1)+value, cell)
+			if (i+1)%columns == 0 || i == len(f.CPU.CPUs)-1 {
+				lines = append(lines, row)
+				row = ""
+			}
+		}
+	}
+	if nil != f.Memory {
+		used := f.Memory.Total - f.Memory.Available
+		swap := f.Memory.SwapTotal - f.Memory.SwapFree
+		for _, it := range []struct {
+			label string
+			used  int64
+			total int64
+		}{
+			{"Mem", used, f.Memory.Total},
+			{"Swap", swap, f.Memory.SwapTotal},
+		} {
+			percent := 0.0
+			if it.total > 0 {
+				percent = float64(it.used) / float64(it.total) * 100
+			}
+			value := fmt.Sprintf(" %s / %s", FormatBytes(float64(it.used)), FormatBytes(float64(it.total)))
+			label := fmt.Sprintf("%-6s", it.label)
+			lines = append(lines, label+Bar(percent, width/2-len(label))+value)
+		}
+	}
+
+	lines = append(lines, "")
+	styles[len(lines)] = EscapeBold
+	lines = append(lines, fmt.Sprintf("%-12s %12s %12s   %-12s %12s %12s %6s",
+		"INTERFACE", "RX/S", "TX/S", "DEVICE", "READ/S", "WRITTEN/S", "UTIL%"))
+	network := append(sysinfo.NetworkRates(nil), f.Network...)
+	sort.SliceStable(network, func(i, j int) bool {
+		return network[i].ReceivedBytes+network[i].TransmittedBytes > network[j].ReceivedBytes+network[j].TransmittedBytes
+	})
+	disks := append(sysinfo.DiskRates(nil), f.Disks...)
+	sort.SliceStable(disks, func(i, j int) bool {
+		return disks[i].ReadBytes+disks[i].WriteBytes > disks[j].ReadBytes+disks[j].WriteBytes
+	})
+	for i := 0; i < _TopDevices && (i < len(network) || i < len(disks)); i++ {
+		left := strings.Repeat(" ", 38)
+		if i < len(network) {
+			left = fmt.Sprintf("%-12s %12s %12s",
+				network[i].Interface, FormatBytes(network[i].ReceivedBytes), FormatBytes(network[i].TransmittedBytes))
+		}
+		right := ""
+		if i < len(disks) {
+			right = fmt.Sprintf("%-12s %12s %12s %6.1f",
+				disks[i].Device, FormatBytes(disks[i].ReadBytes), FormatBytes(disks[i].WriteBytes), disks[i].Utilisation)
+		}
+		lines = append(lines, left+"   "+right)
+	}
+
+	lines = append(lines, "")
+	styles[len(lines)] = EscapeReverse
+	lines = append(lines, fmt.Sprintf("%7s %7s %-2s %4s %10s %6s %6s %-s",
+		"PID", "PPID", "S", "THR", "RSS", "CPU%", "MEM%", "COMMAND"))
+	for _, process := range f.Processes {
+		if len(lines) >= height-1 {
+			break
+		}
+		memory := 0.0
+		if nil != f.Memory && f.Memory.Total > 0 {
+			memory = float64(process.ResidentSize) / float64(f.Memory.Total) * 100
+		}
+		lines = append(lines, fmt.Sprintf("%7d %7d %-2s %4d %10s %6.1f %6.1f %s",
+			process.Pid, process.ParentPid, process.State, process.Threads,
+			FormatBytes(float64(process.ResidentSize)), process.CPU, memory, process.Name))
+	}
+	for len(lines) < height-1 {
+		lines = append(lines, "")
+	}
+	lines = append(lines, fmt.Sprintf("sort: %s   c cpu  m memory  p pid  n name  q quit", f.Sort))
+
+	// Styles wrap the fitted text, so escape sequences never count towards
+	// the width.
+	for i, line := range lines {
+		lines[i] = _Fit(line, width)
+		if style, ok := styles[i]; ok {
+			lines[i] = style + lines[i] + EscapeReset
+		}
+	}
+	return lines
+}
+
+// Frame collects everything top shows.
+func (s *Session) Frame(key string) (*TopFrame, error) {
+	frame := &TopFrame{At: time.Now(), Sort: key}
+	uptime, err := s.collector.GetUptime()
+	if nil != err {
+		return nil, err
+	}
+	frame.Uptime = time.Duration(uptime.Total * float64(time.Second))
+	if frame.Load, err = s.collector.GetLoadAvg(); nil != err {
+		return nil, err
+	}
+	if frame.CPU, err = s.cpu.Sample(); nil != err {
+		return nil, err
+	}
+	if frame.Memory, err = s.collector.GetMemInfo(); nil != err {
+		return nil, err
+	}
+	if stats, err := s.collector.GetNetworkStats(); nil == err {
+		frame.Network = s.network.Update(stats, frame.At)
+	}
+	if stats, err := s.collector.GetDiskStats(); nil == err {
+		frame.Disks = s.disk.Update(stats, frame.At)
+	}
+	if frame.Processes, err = s.ProcessReports(); nil != err {
+		return nil, err
+	}
+	SortProcesses(frame.Processes, key)
+	return frame, nil
+}
+
+// RunTop redraws the frame every interval until q is pressed, the process
+// is interrupted or count frames were drawn. Keys are only read when
+// standard input is a terminal.
+func RunTop(session *Session, options *Options, stdout io.Writer) error {
+	var (
+		interval = options.Watch
+		key      = options.Sort
+		keys     = make(chan byte)
+		signals  = make(chan os.Signal, 1)
+	)
+	if interval <= 0 {
+		interval = DefaultTopInterval
+	}
+	if restore, err := MakeRaw(os.Stdin.Fd()); nil == err {
+		defer func() {
+			_ = restore()
+		}()
+		go func() {
+			buffer := make([]byte, 1)
+			for {
+				if _, err := os.Stdin.Read(buffer); nil != err {
+					return
+				}
+				keys <- buffer[0]
+			}
+		}()
+	}
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGWINCH)
+	defer signal.Stop(signals)
+
+	_, _ = io.WriteString(stdout, EscapeAlternateScreen+EscapeHideCursor)
+	defer func() {
+		_, _ = io.WriteString(stdout, EscapeShowCursor+EscapeMainScreen)
+	}()
+
+	// The first samples only establish the baseline for rates.
+	if _, err := session.Frame(key); nil != err {
+		return err
+	}
+	time.Sleep(_TopPrime)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var frame *TopFrame
+	for count := 1; ; count++ {
+		var err error
+		if frame, err = session.Frame(key); nil != err {
+			return err
+		}
+		if err := DrawTop(stdout, frame); nil != err {
+			return err
+		}
+		if options.Count > 0 && count >= options.Count {
+			return nil
+		}
+	wait:
+		for {
+			select {
+			case <-ticker.C:
+				break wait
+			case sig := <-signals:
+				if sig != syscall.SIGWINCH {
+					return nil
+				}
+				if err := DrawTop(stdout, frame); nil != err {
+					return err
+				}
+			case pressed := <-keys:
+				if pressed == 'q' || pressed == 'Q' {
+					return nil
+				}
+				if sorting, ok := TopKeys[pressed]; ok {
+					key = sorting
+					frame.Sort = key
+					SortProcesses(frame.Processes, key)
+					if err := DrawTop(stdout, frame); nil != err {
+						return err
+					}
+				}
+			}
+		}
+	}
+}
+
+// DrawTop redraws the screen in place, sized to the terminal when there is
+// one.
+func DrawTop(w io.Writer, frame *TopFrame) error {
+	width, height, err := TerminalSize(os.Stdout.Fd())
+	if nil != err || width <= 0 || height <= 0 {
+		width, height = 80, 24
+	}
+	lines := frame.Render(width, height)
+	_, err = io.WriteString(w, EscapeHome+strings.Join(lines, "\r\n")+EscapeClearBelow)
+	return err
+}
@@ -0,0 +1,452 @@
+// Package server serves the sysinfo-go collectors as a JSON API:
+//
+//	http.ListenAndServe(":9100", server.New(nil, 0))
+//
+// Endpoints are /cpu, /memory, /disks, /network, /filesystems,
+// /processes/{pid}, /uname and /snapshot, which combines all but processes.
+// Collections are cached for a maximum age and carry an ETag and
+// Last-Modified derived from the collection time, so polling clients get
+// 304 Not Modified until the next collection.
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	sysinfo "github.com/thebagchi/sysinfo-go"
+)
+
+// DefaultMaxAge is how long a collection is reused when New is given none.
+const DefaultMaxAge = time.Second
+
+const (
+	EndpointCPU         = "/cpu"
+	EndpointMemory      = "/memory"
+	EndpointDisks       = "/disks"
+	EndpointNetwork     = "/network"
+	EndpointFileSystems = "/filesystems"
+	EndpointProcesses   = "/processes/"
+	EndpointUName       = "/uname"
+	EndpointSnapshot    = "/snapshot"
+)
+
+// Query parameters filtering the results. Each may be repeated or hold a
+// comma separated list.
+const (
+	QueryCPU        = "cpu"
+	QueryDevice     = "device"
+	QueryInterface  = "interface"
+	QueryMountPoint = "mountpoint"
+	QueryType       = "type"
+)
+
+type CPU struct {
+	Processors []sysinfo.ProcessorInformation `json:"processors"`
+	Usage      *sysinfo.CPUUsage              `json:"usage"`
+}
+
+// Disks holds the counters since boot and the rates since the previous
+// collection, empty on the first.
+type Disks struct {
+	Stats sysinfo.DiskStats `json:"stats"`
+	Rates sysinfo.DiskRates `json:"rates"`
+}
+
+type Network struct {
+	Stats sysinfo.NetworkStats `json:"stats"`
+	Rates sysinfo.NetworkRates `json:"rates"`
+}
+
+// Snapshot combines every endpoint but processes. Sections that failed are
+// left nil and their errors reported by endpoint name.
+type Snapshot struct {
+	CPU         *CPU                     `json:"cpu"`
+	Memory      *sysinfo.MemInfo         `json:"memory"`
+	Disks       *Disks                   `json:"disks"`
+	Network     *Network                 `json:"network"`
+	FileSystems sysinfo.FileSystemUsages `json:"filesystems"`
+	UName       *sysinfo.UName           `json:"uname"`
+	Errors      map[string]string        `json:"errors,omitempty"`
+}
+
+// _Entry caches the collection of one key. Its mutex serialises the
+// collections of that key only; used and users, guarded by the server
+// mutex, tell when the key was last asked for and by how many requests
+// still waiting on it.
+type _Entry struct {
+	mutex sync.Mutex
+	value interface{}
+	at    time.Time
+	used  time.Time
+	users int
+}
+
+type Server struct {
+	collector *sysinfo.Collector
+	maxAge    time.Duration
+	cpu       *sysinfo.CPUSampler
+	disk      *sysinfo.DiskRateSampler
+	network   *sysinfo.NetworkRateSampler
+	mutex     sync.Mutex
+	cache     map[string]*_Entry
+	mux       *http.ServeMux
+}
+
+// New returns a server reading from collector, nil for
+// sysinfo.DefaultCollector, that reuses collections for maxAge, 0 for
+// DefaultMaxAge.
+func New(collector *sysinfo.Collector, maxAge time.Duration) *Server {
+	if nil == collector {
+		collector = sysinfo.DefaultCollector
+	}
+	if maxAge <= 0 {
+		maxAge = DefaultMaxAge
+	}
+	s := &Server{
+		collector: collector,
+		maxAge:    maxAge,
+		cpu:       sysinfo.NewCPUSampler(collector),
+		disk:      sysinfo.NewDiskRateSampler(collector),
+		network:   sysinfo.NewNetworkRateSampler(collector),
+		cache:     make(map[string]*_Entry),
+		mux:       http.NewServeMux(),
+	}
+	s.mux.HandleFunc(EndpointCPU, s._Handle(s._CPU))
+	s.mux.HandleFunc(EndpointMemory, s._Handle(s._Memory))
+	s.mux.HandleFunc(EndpointDisks, s._Handle(s._Disks))
+	s.mux.HandleFunc(EndpointNetwork, s._Handle(s._Network))
+	s.mux.HandleFunc(EndpointFileSystems, s._Handle(s._FileSystems))
+	s.mux.HandleFunc(EndpointProcesses, s._Handle(s._Process))
+	s.mux.HandleFunc(EndpointUName, s._Handle(s._UName))
+	s.mux.HandleFunc(EndpointSnapshot, s._Handle(s._Snapshot))
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// _StatusError carries the HTTP status an endpoint fails with.
+type _StatusError struct {
+	status int
+	err    error
+}
+
+func (e *_StatusError) Error() string {
+	return e.err.Error()
+}
+
+func (e *_StatusError) Unwrap() error {
+	return e.err
+}
+
+// _Collect returns the cached value of key while it is younger than the
+// maximum age and collects it anew otherwise. Collections of the same key
+// are serialised, so concurrent requests share one, while other keys are
+// collected in parallel.
+func (s *Server) _Collect(key string, collect func() (interface{}, error)) (interface{}, time.Time, error) {
+	entry := s._Entry(key)
+	defer s._Release(entry)
+	entry.mutex.Lock()
+	defer entry.mutex.Unlock()
+	now := time.Now()
+	if !entry.at.IsZero() && now.Sub(entry.at) < s.maxAge {
+		return entry.value, entry.at, nil
+	}
+	value, err := collect()
+	if nil != err {
+		return nil, time.Time{}, err
+	}
+	entry.value, entry.at = value, now
+	return value, now, nil
+}
+
+// _Entry returns the cache entry of key, creating it when needed, and drops
+// idle entries not asked for within the maximum age, such as ones of
+// processes no longer polled.
+func (s *Server) _Entry(key string) *_Entry {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	now := time.Now()
+	for k, entry := range s.cache {
+		if entry.users == 0 && now.Sub(entry.used) >= s.maxAge {
+			delete(s.cache, k)
+		}
+	}
+	entry, ok := s.cache[key]
+	if !ok {
+		entry = new(_Entry)
+		s.cache[key] = entry
+	}
+	entry.used = now
+	entry.users++
+	return entry
+}
+
+func (s *Server) _Release(entry *_Entry) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	entry.users--
+}
+
+func _Filter(r *http.Request, name string) map[string]bool {
+	values := r.URL.Query()[name]
+	if len(values) == 0 {
+		return nil
+	}
+	selected := make(map[string]bool)
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); len(item) > 0 {
+				selected[item] = true
+			}
+		}
+	}
+	return selected
+}
+
+// _Selected tells whether value passes a filter, where a nil filter passes
+// everything.
+func _Selected(filter map[string]bool, value string) bool {
+	return nil == filter || filter[value]
+}
+
+func (s *Server) _CPU(r *http.Request) (interface{}, time.Time, error) {
+	value, at, err := s._Collect(EndpointCPU, func() (interface{}, error) {
+		info, err := s.collector.GetCPUInfo()
+		if nil != err {
+			return nil, err
+		}
+		usage, err := s.cpu.Sample()
+		if nil != err {
+			return nil, err
+		}
+		return &CPU{Processors: info.Processors, Usage: usage}, nil
+	})
+	if nil != err {
+		return nil, at, err
+	}
+	var (
+		cpu    = value.(*CPU)
+		filter = _Filter(r, QueryCPU)
+	)
+	if nil == filter {
+		return cpu, at, nil
+	}
+	filtered := &CPU{
+		Processors: make([]sysinfo.ProcessorInformation, 0),
+		Usage: &sysinfo.CPUUsage{
+			Interval: cpu.Usage.Interval,
+			Total:    cpu.Usage.Total,
+			CPUs:     make([]sysinfo.CPUUtilisation, 0),
+		},
+	}
+	for _, processor := range cpu.Processors {
+		if filter[sysinfo.StatCPU+strconv.FormatInt(processor.Id, 10)] {
+			filtered.Processors = append(filtered.Processors, processor)
+		}
+	}
+	for _, utilisation := range cpu.Usage.CPUs {
+		if filter[utilisation.CPUId] {
+			filtered.Usage.CPUs = append(filtered.Usage.CPUs, utilisation)
+		}
+	}
+	return filtered, at, nil
+}
+
+func (s *Server) _Memory(r *http.Request) (interface{}, time.Time, error) {
+	return s._Collect(EndpointMemory, func() (interface{}, error) {
+		return s.collector.GetMemInfo()
+	})
+}
+
+func (s *Server) _Disks(r *http.Request) (interface{}, time.Time, error) {
+	value, at, err := s._Collect(EndpointDisks, func() (interface{}, error) {
+		stats, err := s.collector.GetDiskStats()
+		if nil != err {
+			return nil, err
+		}
+		return &Disks{Stats: stats, Rates: s.disk.Update(stats, time.Now())}, nil
+	})
+	if nil != err {
+		return nil, at, err
+	}
+	var (
+		disks    = value.(*Disks)
+		filter   = _Filter(r, QueryDevice)
+		filtered = &Disks{Stats: make(sysinfo.DiskStats, 0), Rates: make(sysinfo.DiskRates, 0)}
+	)
+	for _, stat := range disks.Stats {
+		if _Selected(filter, stat.Device) {
+			filtered.Stats = append(filtered.Stats, stat)
+		}
+	}
+	for _, rate := range disks.Rates {
+		if _Selected(filter, rate.Device) {
+			filtered.Rates = append(filtered.Rates, rate)
+		}
+	}
+	return filtered, at, nil
+}
+
+func (s *Server) _Network(r *http.Request) (interface{}, time.Time, error) {
+	value, at, err := s._Collect(EndpointNetwork, func() (interface{}, error) {
+		stats, err := s.collector.GetNetworkStats()
+		if nil != err {
+			return nil, err
+		}
+		return &Network{Stats: stats, Rates: s.network.Update(stats, time.Now())}, nil
+	})
+	if nil != err {
+		return nil, at, err
+	}
+	var (
+		network  = value.(*Network)
+		filter   = _Filter(r, QueryInterface)
+		filtered = &Network{Stats: make(sysinfo.NetworkStats, 0), Rates: make(sysinfo.NetworkRates, 0)}
+	)
+	for _, stat := range network.Stats {
+		if _Selected(filter, stat.Interface) {
+			filtered.Stats = append(filtered.Stats, stat)
+		}
+	}
+	for _, rate := range network.Rates {
+		if _Selected(filter, rate.Interface) {
+			filtered.Rates = append(filtered.Rates, rate)
+		}
+	}
+	return filtered, at, nil
+}
+
+func (s *Server) _FileSystems(r *http.Request) (interface{}, time.Time, error) {
+	value, at, err := s._Collect(EndpointFileSystems, func() (interface{}, error) {
+		return s.collector.GetFileSystemUsage()
+	})
+	if nil != err {
+		return nil, at, err
+	}
+	var (
+		devices     = _Filter(r, QueryDevice)
+		mountPoints = _Filter(r, QueryMountPoint)
+		types       = _Filter(r, QueryType)
+		filtered    = make(sysinfo.FileSystemUsages, 0)
+	)
+	for _, usage := range value.(sysinfo.FileSystemUsages) {
+		if _Selected(devices, usage.Mount.Source) &&
+			_Selected(mountPoints, usage.Mount.MountPoint) &&
+			_Selected(types, usage.Mount.FileSystemType) {
+			filtered = append(filtered, usage)
+		}
+	}
+	return filtered, at, nil
+}
+
+func (s *Server) _Process(r *http.Request) (interface{}, time.Time, error) {
+	name := strings.TrimPrefix(r.URL.Path, EndpointProcesses)
+	pid, err := strconv.Atoi(name)
+	if nil != err || pid <= 0 || strings.Contains(name, "/") {
+		return nil, time.Time{}, &_StatusError{http.StatusNotFound, fmt.Errorf("invalid process id %q", name)}
+	}
+	value, at, err := s._Collect(EndpointProcesses+name, func() (interface{}, error) {
+		return s.collector.GetProcess(pid)
+	})
+	if errors.Is(err, sysinfo.ErrProcessNotFound) {
+		return nil, at, &_StatusError{http.StatusNotFound, err}
+	}
+	return value, at, err
+}
+
+func (s *Server) _UName(r *http.Request) (interface{}, time.Time, error) {
+	return s._Collect(EndpointUName, func() (interface{}, error) {
+		return sysinfo.GetUName()
+	})
+}
+
+// _Snapshot applies the filters of the request to every section. It changes
+// whenever one of its sections is collected anew, so it carries the time of
+// the newest.
+func (s *Server) _Snapshot(r *http.Request) (interface{}, time.Time, error) {
+	var (
+		snapshot = &Snapshot{Errors: make(map[string]string)}
+		newest   time.Time
+	)
+	sections := []struct {
+		name    string
+		handler func(r *http.Request) (interface{}, time.Time, error)
+		assign  func(value interface{})
+	}{
+		{"cpu", s._CPU, func(v interface{}) { snapshot.CPU = v.(*CPU) }},
+		{"memory", s._Memory, func(v interface{}) { snapshot.Memory = v.(*sysinfo.MemInfo) }},
+		{"disks", s._Disks, func(v interface{}) { snapshot.Disks = v.(*Disks) }},
+		{"network", s._Network, func(v interface{}) { snapshot.Network = v.(*Network) }},
+		{"filesystems", s._FileSystems, func(v interface{}) { snapshot.FileSystems = v.(sysinfo.FileSystemUsages) }},
+		{"uname", s._UName, func(v interface{}) { snapshot.UName = v.(*sysinfo.UName) }},
+	}
+	for _, section := range sections {
+		value, at, err := section.handler(r)
+		if nil != err {
+			snapshot.Errors[section.name] = err.Error()
+			continue
+		}
+		section.assign(value)
+		if at.After(newest) {
+			newest = at
+		}
+	}
+	if len(snapshot.Errors) == len(sections) {
+		return nil, newest, errors.New("every section of the snapshot failed")
+	}
+	return snapshot, newest, nil
+}
+
+func _WriteError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	var statusError *_StatusError
+	if errors.As(err, &statusError) {
+		status = statusError.status
+	}
+	data, _ := json.Marshal(map[string]string{"error": err.Error()})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(append(data, '\n'))
+}
+
+// _ETag identifies a collection together with the query filtering it.
+func _ETag(r *http.Request, at time.Time) string {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(r.URL.Path + "?" + r.URL.RawQuery))
+	return fmt.Sprintf(`"%x-%x"`, at.UnixNano(), hash.Sum64())
+}
+
+func (s *Server) _Handle(handler func(r *http.Request) (interface{}, time.Time, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			_WriteError(w, &_StatusError{http.StatusMethodNotAllowed, errors.New("method not allowed")})
+			return
+		}
+		value, at, err := handler(r)
+		if nil != err {
+			_WriteError(w, err)
+			return
+		}
+		data, err := json.Marshal(value)
+		if nil != err {
+			_WriteError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", _ETag(r, at))
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", int(s.maxAge.Seconds())))
+		// ServeContent answers If-None-Match and If-Modified-Since.
+		http.ServeContent(w, r, "", at, bytes.NewReader(append(data, '\n')))
+	}
+}
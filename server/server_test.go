@@ -0,0 +1,137 @@
+package server
+
+import (
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	sysinfo "github.com/thebagchi/sysinfo-go"
+)
+
+func TestServer(t *testing.T) {
+	fsys := fstest.MapFS{
+		"proc/meminfo":   &fstest.MapFile{Data: []byte("MemTotal: 1024 kB\nMemFree: 512 kB\n")},
+		"proc/diskstats": &fstest.MapFile{Data: []byte("   8       0 sda 10 0 8 1500 0 0 0 0 0 0 0\n   8      16 sdb 1 0 8 1 0 0 0 0 0 0 0\n")},
+		"proc/stat":      &fstest.MapFile{Data: []byte("btime 1700000000\n")},
+	}
+	server := New(sysinfo.NewCollector(fsys, "proc", "sys"), time.Hour)
+	get := func(target string, header http.Header) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, target, nil)
+		for k, v := range header {
+			request.Header[k] = v
+		}
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	response := get("/memory", nil)
+	if response.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", response.Code, response.Body)
+	}
+	mem := new(sysinfo.MemInfo)
+	if err := json.Unmarshal(response.Body.Bytes(), mem); nil != err {
+		t.Fatal(err)
+	}
+	if mem.Total != 1<<20 {
+		t.Errorf("unexpected memory %+v", mem)
+	}
+	etag := response.Header().Get("ETag")
+	if len(etag) == 0 {
+		t.Fatal("missing etag")
+	}
+	if response := get("/memory", http.Header{"If-None-Match": {etag}}); response.Code != http.StatusNotModified {
+		t.Errorf("expected not modified, got %d", response.Code)
+	}
+
+	response = get("/disks?device=sdb", nil)
+	disks := new(Disks)
+	if err := json.Unmarshal(response.Body.Bytes(), disks); nil != err {
+		t.Fatal(err)
+	}
+	if len(disks.Stats) != 1 || disks.Stats[0].Device != "sdb" {
+		t.Errorf("unexpected disks %+v", disks)
+	}
+	if get("/disks?device=sda", nil).Header().Get("ETag") == get("/disks?device=sdb", nil).Header().Get("ETag") {
+		t.Error("expected filters to change the etag")
+	}
+
+	if response := get("/processes/1", nil); response.Code != http.StatusNotFound {
+		t.Errorf("expected not found for missing process, got %d", response.Code)
+	}
+	if response := get("/processes/abc", nil); response.Code != http.StatusNotFound {
+		t.Errorf("expected not found for invalid pid, got %d", response.Code)
+	}
+
+	response = get("/snapshot", nil)
+	snapshot := new(Snapshot)
+	if err := json.Unmarshal(response.Body.Bytes(), snapshot); nil != err {
+		t.Fatal(err)
+	}
+	if nil == snapshot.Memory || nil == snapshot.Disks || len(snapshot.Errors["network"]) == 0 {
+		t.Errorf("unexpected snapshot %+v", snapshot)
+	}
+}
+
+// _BlockingFS blocks opening one file until released, like statfs on a hung
+// network filesystem.
+type _BlockingFS struct {
+	files   fstest.MapFS
+	name    string
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (b *_BlockingFS) Open(name string) (fs.File, error) {
+	if name == b.name {
+		close(b.entered)
+		<-b.release
+	}
+	return b.files.Open(name)
+}
+
+func TestServerSlowCollection(t *testing.T) {
+	fsys := &_BlockingFS{
+		files: fstest.MapFS{
+			"proc/meminfo":        &fstest.MapFile{Data: []byte("MemTotal: 1024 kB\nMemFree: 512 kB\n")},
+			"proc/self/mountinfo": &fstest.MapFile{Data: []byte("22 1 8:1 / / rw - ext4 /dev/sda1 rw\n")},
+		},
+		name:    "proc/self/mountinfo",
+		entered: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	server := New(sysinfo.NewCollector(fsys, "proc", "sys"), time.Hour)
+	get := func(target string) int {
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+		return recorder.Code
+	}
+	slow := make(chan int)
+	go func() {
+		slow <- get("/filesystems")
+	}()
+	<-fsys.entered
+
+	fast := make(chan int)
+	go func() {
+		fast <- get("/memory")
+	}()
+	select {
+	case code := <-fast:
+		if code != http.StatusOK {
+			t.Errorf("unexpected status %d", code)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("memory blocked behind the filesystems collection")
+		close(fsys.release)
+		<-fast
+		<-slow
+		return
+	}
+	close(fsys.release)
+	<-slow
+}
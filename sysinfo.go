@@ -3,8 +3,8 @@ package sysinfo_go
 import (
 	"bytes"
 	"errors"
+	"io/fs"
 	"net"
-	"os"
 	"reflect"
 	"strconv"
 	"strings"
@@ -38,6 +38,7 @@ const (
 	CPUInfoCPUFrequency   = "cpu MHz"
 	CPUInfoCacheSize      = "cache size"
 	CPUInfoCacheAlignment = "cache_alignment"
+	CPUInfoFlags          = "flags"
 )
 
 const (
@@ -52,31 +53,145 @@ const (
 )
 
 const (
-	MemInfoMemTotal     = "MemTotal"
-	MemInfoMemFree      = "MemFree"
-	MemInfoMemAvailable = "MemAvailable"
-	MemInfoBuffered     = "Buffers"
-	MemInfoCached       = "Cached"
-	MemInfoSwapCached   = "SwapCached"
-	MemInfoSwapTotal    = "SwapTotal"
-	MemInfoSwapFree     = "SwapFree"
+	MemInfoMemTotal            = "MemTotal"
+	MemInfoMemFree             = "MemFree"
+	MemInfoMemAvailable        = "MemAvailable"
+	MemInfoBuffered            = "Buffers"
+	MemInfoCached              = "Cached"
+	MemInfoSwapCached          = "SwapCached"
+	MemInfoActive              = "Active"
+	MemInfoInactive            = "Inactive"
+	MemInfoActiveAnon          = "Active(anon)"
+	MemInfoInactiveAnon        = "Inactive(anon)"
+	MemInfoActiveFile          = "Active(file)"
+	MemInfoInactiveFile        = "Inactive(file)"
+	MemInfoUnevictable         = "Unevictable"
+	MemInfoMlocked             = "Mlocked"
+	MemInfoHighTotal           = "HighTotal"
+	MemInfoHighFree            = "HighFree"
+	MemInfoLowTotal            = "LowTotal"
+	MemInfoLowFree             = "LowFree"
+	MemInfoMmapCopy            = "MmapCopy"
+	MemInfoSwapTotal           = "SwapTotal"
+	MemInfoSwapFree            = "SwapFree"
+	MemInfoZswap               = "Zswap"
+	MemInfoZswapped            = "Zswapped"
+	MemInfoDirty               = "Dirty"
+	MemInfoWriteback           = "Writeback"
+	MemInfoAnonPages           = "AnonPages"
+	MemInfoMapped              = "Mapped"
+	MemInfoShmem               = "Shmem"
+	MemInfoKernelReclaimable   = "KReclaimable"
+	MemInfoSlab                = "Slab"
+	MemInfoSlabReclaimable     = "SReclaimable"
+	MemInfoSlabUnreclaimable   = "SUnreclaim"
+	MemInfoKernelStack         = "KernelStack"
+	MemInfoPageTables          = "PageTables"
+	MemInfoSecondaryPageTables = "SecPageTables"
+	MemInfoNFSUnstable         = "NFS_Unstable"
+	MemInfoBounce              = "Bounce"
+	MemInfoWritebackTmp        = "WritebackTmp"
+	MemInfoCommitLimit         = "CommitLimit"
+	MemInfoCommittedAS         = "Committed_AS"
+	MemInfoVmallocTotal        = "VmallocTotal"
+	MemInfoVmallocUsed         = "VmallocUsed"
+	MemInfoVmallocChunk        = "VmallocChunk"
+	MemInfoPercpu              = "Percpu"
+	MemInfoHardwareCorrupted   = "HardwareCorrupted"
+	MemInfoAnonHugePages       = "AnonHugePages"
+	MemInfoShmemHugePages      = "ShmemHugePages"
+	MemInfoShmemPmdMapped      = "ShmemPmdMapped"
+	MemInfoFileHugePages       = "FileHugePages"
+	MemInfoFilePmdMapped       = "FilePmdMapped"
+	MemInfoCmaTotal            = "CmaTotal"
+	MemInfoCmaFree             = "CmaFree"
+	MemInfoUnaccepted          = "Unaccepted"
+	MemInfoBalloon             = "Balloon"
+	MemInfoHugePagesTotal      = "HugePages_Total"
+	MemInfoHugePagesFree       = "HugePages_Free"
+	MemInfoHugePagesReserved   = "HugePages_Rsvd"
+	MemInfoHugePagesSurplus    = "HugePages_Surp"
+	MemInfoHugePageSize        = "Hugepagesize"
+	MemInfoHugetlb             = "Hugetlb"
+	MemInfoDirectMap4k         = "DirectMap4k"
+	MemInfoDirectMap2M         = "DirectMap2M"
+	MemInfoDirectMap4M         = "DirectMap4M"
+	MemInfoDirectMap1G         = "DirectMap1G"
 )
 
+const (
+	VMStatNrFreePages                = "nr_free_pages"
+	VMStatNrInactiveAnon             = "nr_inactive_anon"
+	VMStatNrActiveAnon               = "nr_active_anon"
+	VMStatNrInactiveFile             = "nr_inactive_file"
+	VMStatNrActiveFile               = "nr_active_file"
+	VMStatNrAnonPages                = "nr_anon_pages"
+	VMStatNrMapped                   = "nr_mapped"
+	VMStatNrFilePages                = "nr_file_pages"
+	VMStatNrDirty                    = "nr_dirty"
+	VMStatNrWriteback                = "nr_writeback"
+	VMStatNrShmem                    = "nr_shmem"
+	VMStatNrSlabReclaimable          = "nr_slab_reclaimable"
+	VMStatNrSlabUnreclaimable        = "nr_slab_unreclaimable"
+	VMStatNrAnonTransparentHugePages = "nr_anon_transparent_hugepages"
+	VMStatNrDirtied                  = "nr_dirtied"
+	VMStatNrWritten                  = "nr_written"
+	VMStatPageIn                     = "pgpgin"
+	VMStatPageOut                    = "pgpgout"
+	VMStatSwapIn                     = "pswpin"
+	VMStatSwapOut                    = "pswpout"
+	VMStatPageFree                   = "pgfree"
+	VMStatPageActivate               = "pgactivate"
+	VMStatPageDeactivate             = "pgdeactivate"
+	VMStatPageFault                  = "pgfault"
+	VMStatPageMajorFault             = "pgmajfault"
+	VMStatPageRefill                 = "pgrefill"
+	VMStatPageStealKswapd            = "pgsteal_kswapd"
+	VMStatPageStealDirect            = "pgsteal_direct"
+	VMStatPageScanKswapd             = "pgscan_kswapd"
+	VMStatPageScanDirect             = "pgscan_direct"
+	VMStatSlabsScanned               = "slabs_scanned"
+	VMStatKswapdInodeSteal           = "kswapd_inodesteal"
+	VMStatPageInodeSteal             = "pginodesteal"
+	VMStatOOMKill                    = "oom_kill"
+	VMStatCompactStall               = "compact_stall"
+	VMStatCompactFail                = "compact_fail"
+	VMStatCompactSuccess             = "compact_success"
+	VMStatTHPFaultAlloc              = "thp_fault_alloc"
+	VMStatTHPFaultFallback           = "thp_fault_fallback"
+	VMStatTHPCollapseAlloc           = "thp_collapse_alloc"
+	VMStatTHPCollapseAllocFailed     = "thp_collapse_alloc_failed"
+	VMStatTHPSplitPage               = "thp_split_page"
+	VMStatTHPSplitPageFailed         = "thp_split_page_failed"
+	VMStatTHPZeroPageAlloc           = "thp_zero_page_alloc"
+	VMStatTHPSwpout                  = "thp_swpout"
+)
+
+// FastStringToBytes and FastBytesToString share the memory of their
+// argument instead of copying it. The headers are only ever taken of real
+// strings and slices, the one use of reflect.StringHeader and
+// reflect.SliceHeader that unsafe.Pointer allows.
 func FastStringToBytes(data string) []byte {
-	hdr := *(*reflect.StringHeader)(unsafe.Pointer(&data))
-	return *(*[]byte)(unsafe.Pointer(&reflect.SliceHeader{
-		Data: hdr.Data,
-		Len:  hdr.Len,
-		Cap:  hdr.Len,
-	}))
+	var (
+		result []byte
+		source = (*reflect.StringHeader)(unsafe.Pointer(&data))
+		target = (*reflect.SliceHeader)(unsafe.Pointer(&result))
+	)
+	target.Data = source.Data
+	target.Len = source.Len
+	target.Cap = source.Len
+	return result
 }
 
 func FastBytesToString(data []byte) string {
-	hdr := *(*reflect.SliceHeader)(unsafe.Pointer(&data))
-	return *(*string)(unsafe.Pointer(&reflect.StringHeader{
-		Data: hdr.Data,
-		Len:  hdr.Len,
-	}))
+	var (
+		result string
+		source = (*reflect.SliceHeader)(unsafe.Pointer(&data))
+		target = (*reflect.StringHeader)(unsafe.Pointer(&result))
+	)
+	target.Data = source.Data
+	target.Len = source.Len
+	return result
 }
 
 func GetNetworkInterface() (NetworkInterfaces, error) {
@@ -102,29 +217,29 @@ func GetNetworkInterface() (NetworkInterfaces, error) {
 	return interfaces, nil
 }
 
-func _MakeUptime(uptime int64) string {
-	duration := time.Duration(uptime) * time.Second
-	return duration.String()
-}
-
-func GetSystemInformation() (*SystemInformation, error) {
+// _SystemInformation scales the sizes of sysinfo(2), which are in multiples
+// of Unit, to bytes. Kernels before 2.3.23 leave Unit at zero.
+func _SystemInformation(si *syscall.Sysinfo_t, now time.Time) *SystemInformation {
 	const (
 		scale = float64(1 << 16)
 	)
-	var (
-		si                      = &syscall.Sysinfo_t{}
-		err                     = syscall.Sysinfo(si)
-		info *SystemInformation = nil
-	)
-	if nil != err {
-		return nil, err
+	unit := uint64(si.Unit)
+	if unit == 0 {
+		unit = 1
 	}
-	info = &SystemInformation{
-		Uptime:        _MakeUptime(si.Uptime),
-		TotalRam:      si.Totalram,
-		AvailableRam:  si.Freeram,
-		TotalSwap:     si.Totalswap,
-		AvailableSwap: si.Freeswap,
+	uptime := time.Duration(si.Uptime) * time.Second
+	return &SystemInformation{
+		Uptime:        uptime,
+		BootTime:      now.Add(-uptime).Truncate(time.Second),
+		TotalRam:      uint64(si.Totalram) * unit,
+		AvailableRam:  uint64(si.Freeram) * unit,
+		FreeRam:       uint64(si.Freeram) * unit,
+		SharedRam:     uint64(si.Sharedram) * unit,
+		BufferRam:     uint64(si.Bufferram) * unit,
+		TotalSwap:     uint64(si.Totalswap) * unit,
+		AvailableSwap: uint64(si.Freeswap) * unit,
+		TotalHighRam:  uint64(si.Totalhigh) * unit,
+		FreeHighRam:   uint64(si.Freehigh) * unit,
 		Processes:     uint64(si.Procs),
 		Loads: &Load{
 			Load1:  float64(si.Loads[0]) / scale,
@@ -132,14 +247,105 @@ func GetSystemInformation() (*SystemInformation, error) {
 			Load15: float64(si.Loads[2]) / scale,
 		},
 	}
+}
+
+func GetSystemInformation() (*SystemInformation, error) {
+	return DefaultCollector.GetSystemInformation()
+}
+
+// GetSystemInformation reports sysinfo(2) of the running kernel. Available
+// RAM is MemAvailable, the memory that can be allocated without swapping,
+// falling back to the free RAM on kernels before 3.14.
+func (c *Collector) GetSystemInformation() (*SystemInformation, error) {
+	si := &syscall.Sysinfo_t{}
+	if err := syscall.Sysinfo(si); nil != err {
+		return nil, err
+	}
+	info := _SystemInformation(si, time.Now())
+	if mem, err := c.GetMemInfo(); nil == err && mem.Available > 0 {
+		info.AvailableRam = uint64(mem.Available)
+	}
+	// Procs is a 16 bit field and wraps on busy hosts, /proc/loadavg
+	// carries the same count untruncated.
+	if load, err := c.GetLoadAvg(); nil == err && load.Entities > 0 {
+		info.Processes = uint64(load.Entities)
+		info.Loads.Runnable = load.Runnable
+		info.Loads.Entities = load.Entities
+	}
 	return info, nil
 }
 
 func _ParseMemInfo(data []byte) (*MemInfo, error) {
 	var (
-		mem     = new(MemInfo)
+		mem     = &MemInfo{Others: make(map[string]int64)}
 		newline = []byte("\n")
 		colon   = []byte(":")
+		known   = map[string]*int64{
+			MemInfoMemTotal:            &mem.Total,
+			MemInfoMemFree:             &mem.Free,
+			MemInfoMemAvailable:        &mem.Available,
+			MemInfoBuffered:            &mem.Buffered,
+			MemInfoCached:              &mem.Cached,
+			MemInfoSwapCached:          &mem.SwapCached,
+			MemInfoActive:              &mem.Active,
+			MemInfoInactive:            &mem.Inactive,
+			MemInfoActiveAnon:          &mem.ActiveAnon,
+			MemInfoInactiveAnon:        &mem.InactiveAnon,
+			MemInfoActiveFile:          &mem.ActiveFile,
+			MemInfoInactiveFile:        &mem.InactiveFile,
+			MemInfoUnevictable:         &mem.Unevictable,
+			MemInfoMlocked:             &mem.Mlocked,
+			MemInfoHighTotal:           &mem.HighTotal,
+			MemInfoHighFree:            &mem.HighFree,
+			MemInfoLowTotal:            &mem.LowTotal,
+			MemInfoLowFree:             &mem.LowFree,
+			MemInfoMmapCopy:            &mem.MmapCopy,
+			MemInfoSwapTotal:           &mem.SwapTotal,
+			MemInfoSwapFree:            &mem.SwapFree,
+			MemInfoZswap:               &mem.Zswap,
+			MemInfoZswapped:            &mem.Zswapped,
+			MemInfoDirty:               &mem.Dirty,
+			MemInfoWriteback:           &mem.Writeback,
+			MemInfoAnonPages:           &mem.AnonPages,
+			MemInfoMapped:              &mem.Mapped,
+			MemInfoShmem:               &mem.Shmem,
+			MemInfoKernelReclaimable:   &mem.KernelReclaimable,
+			MemInfoSlab:                &mem.Slab,
+			MemInfoSlabReclaimable:     &mem.SlabReclaimable,
+			MemInfoSlabUnreclaimable:   &mem.SlabUnreclaimable,
+			MemInfoKernelStack:         &mem.KernelStack,
+			MemInfoPageTables:          &mem.PageTables,
+			MemInfoSecondaryPageTables: &mem.SecondaryPageTables,
+			MemInfoNFSUnstable:         &mem.NFSUnstable,
+			MemInfoBounce:              &mem.Bounce,
+			MemInfoWritebackTmp:        &mem.WritebackTmp,
+			MemInfoCommitLimit:         &mem.CommitLimit,
+			MemInfoCommittedAS:         &mem.CommittedAS,
+			MemInfoVmallocTotal:        &mem.VmallocTotal,
+			MemInfoVmallocUsed:         &mem.VmallocUsed,
+			MemInfoVmallocChunk:        &mem.VmallocChunk,
+			MemInfoPercpu:              &mem.Percpu,
+			MemInfoHardwareCorrupted:   &mem.HardwareCorrupted,
+			MemInfoAnonHugePages:       &mem.AnonHugePages,
+			MemInfoShmemHugePages:      &mem.ShmemHugePages,
+			MemInfoShmemPmdMapped:      &mem.ShmemPmdMapped,
+			MemInfoFileHugePages:       &mem.FileHugePages,
+			MemInfoFilePmdMapped:       &mem.FilePmdMapped,
+			MemInfoCmaTotal:            &mem.CmaTotal,
+			MemInfoCmaFree:             &mem.CmaFree,
+			MemInfoUnaccepted:          &mem.Unaccepted,
+			MemInfoBalloon:             &mem.Balloon,
+			MemInfoHugePagesTotal:      &mem.HugePagesTotal,
+			MemInfoHugePagesFree:       &mem.HugePagesFree,
+			MemInfoHugePagesReserved:   &mem.HugePagesReserved,
+			MemInfoHugePagesSurplus:    &mem.HugePagesSurplus,
+			MemInfoHugePageSize:        &mem.HugePageSize,
+			MemInfoHugetlb:             &mem.Hugetlb,
+			MemInfoDirectMap4k:         &mem.DirectMap4k,
+			MemInfoDirectMap2M:         &mem.DirectMap2M,
+			MemInfoDirectMap4M:         &mem.DirectMap4M,
+			MemInfoDirectMap1G:         &mem.DirectMap1G,
+		}
 	)
 	lines := bytes.Split(data, newline)
 	for _, line := range lines {
@@ -151,99 +357,39 @@ func _ParseMemInfo(data []byte) (*MemInfo, error) {
 			return nil, errors.New("incorrectly formatted meminfo content")
 		}
 		var (
-			key   = FastBytesToString(bytes.TrimSpace(items[0]))
-			value = FastBytesToString(bytes.TrimSpace(items[1]))
+			key    = FastBytesToString(bytes.TrimSpace(items[0]))
+			fields = bytes.Fields(items[1])
 		)
-		switch key {
-		case MemInfoMemTotal:
-			fields := bytes.Fields(FastStringToBytes(value))
-			if len(fields) != 2 {
-				return nil, errors.New("incorrectly formatted meminfo content")
-			}
-			if v, err := strconv.ParseInt(FastBytesToString(fields[0]), 10, 64); nil != err {
-				return nil, errors.New("incorrectly formatted meminfo content")
-			} else {
-				mem.Total = v
-			}
-		case MemInfoMemFree:
-			fields := bytes.Fields(FastStringToBytes(value))
-			if len(fields) != 2 {
-				return nil, errors.New("incorrectly formatted meminfo content")
-			}
-			if v, err := strconv.ParseInt(FastBytesToString(fields[0]), 10, 64); nil != err {
-				return nil, errors.New("incorrectly formatted meminfo content")
-			} else {
-				mem.Free = v
-			}
-		case MemInfoMemAvailable:
-			fields := bytes.Fields(FastStringToBytes(value))
-			if len(fields) != 2 {
-				return nil, errors.New("incorrectly formatted meminfo content")
-			}
-			if v, err := strconv.ParseInt(FastBytesToString(fields[0]), 10, 64); nil != err {
-				return nil, errors.New("incorrectly formatted meminfo content")
-			} else {
-				mem.Available = v
-			}
-		case MemInfoCached:
-			fields := bytes.Fields(FastStringToBytes(value))
-			if len(fields) != 2 {
-				return nil, errors.New("incorrectly formatted meminfo content")
-			}
-			if v, err := strconv.ParseInt(FastBytesToString(fields[0]), 10, 64); nil != err {
-				return nil, errors.New("incorrectly formatted meminfo content")
-			} else {
-				mem.Cached = v
-			}
-		case MemInfoBuffered:
-			fields := bytes.Fields(FastStringToBytes(value))
-			if len(fields) != 2 {
-				return nil, errors.New("incorrectly formatted meminfo content")
-			}
-			if v, err := strconv.ParseInt(FastBytesToString(fields[0]), 10, 64); nil != err {
-				return nil, errors.New("incorrectly formatted meminfo content")
-			} else {
-				mem.Buffered = v
-			}
-		case MemInfoSwapTotal:
-			fields := bytes.Fields(FastStringToBytes(value))
-			if len(fields) != 2 {
-				return nil, errors.New("incorrectly formatted meminfo content")
-			}
-			if v, err := strconv.ParseInt(FastBytesToString(fields[0]), 10, 64); nil != err {
-				return nil, errors.New("incorrectly formatted meminfo content")
-			} else {
-				mem.SwapTotal = v
-			}
-		case MemInfoSwapFree:
-			fields := bytes.Fields(FastStringToBytes(value))
-			if len(fields) != 2 {
-				return nil, errors.New("incorrectly formatted meminfo content")
-			}
-			if v, err := strconv.ParseInt(FastBytesToString(fields[0]), 10, 64); nil != err {
-				return nil, errors.New("incorrectly formatted meminfo content")
-			} else {
-				mem.SwapFree = v
-			}
-		case MemInfoSwapCached:
-			fields := bytes.Fields(FastStringToBytes(value))
-			if len(fields) != 2 {
-				return nil, errors.New("incorrectly formatted meminfo content")
-			}
-			if v, err := strconv.ParseInt(FastBytesToString(fields[0]), 10, 64); nil != err {
+		if len(fields) == 0 || len(fields) > 2 {
+			return nil, errors.New("incorrectly formatted meminfo content")
+		}
+		value, err := strconv.ParseInt(FastBytesToString(fields[0]), 10, 64)
+		if nil != err {
+			return nil, errors.New("incorrectly formatted meminfo content")
+		}
+		// Sizes are printed in kB, page counts such as HugePages_Total
+		// carry no unit.
+		if len(fields) == 2 {
+			if string(fields[1]) != "kB" {
 				return nil, errors.New("incorrectly formatted meminfo content")
-			} else {
-				mem.SwapCached = v
 			}
-		default:
-			// Do Nothing
+			value = value * 1024
+		}
+		if field, ok := known[key]; ok {
+			*field = value
+		} else {
+			mem.Others[string(bytes.TrimSpace(items[0]))] = value
 		}
 	}
 	return mem, nil
 }
 
 func GetMemInfo() (*MemInfo, error) {
-	contents, err := os.ReadFile(MemInfoFile)
+	return DefaultCollector.GetMemInfo()
+}
+
+func (c *Collector) GetMemInfo() (*MemInfo, error) {
+	contents, err := c._ReadProcFile(MemInfoFile)
 	if nil != err {
 		return nil, err
 	}
@@ -252,13 +398,85 @@ func GetMemInfo() (*MemInfo, error) {
 
 func _ParseVMStat(data []byte) (*VMStat, error) {
 	var (
-		vm = new(VMStat)
+		vm      = &VMStat{Others: make(map[string]int64)}
+		newline = []byte("\n")
+		known   = map[string]*int64{
+			VMStatNrFreePages:                &vm.NrFreePages,
+			VMStatNrInactiveAnon:             &vm.NrInactiveAnon,
+			VMStatNrActiveAnon:               &vm.NrActiveAnon,
+			VMStatNrInactiveFile:             &vm.NrInactiveFile,
+			VMStatNrActiveFile:               &vm.NrActiveFile,
+			VMStatNrAnonPages:                &vm.NrAnonPages,
+			VMStatNrMapped:                   &vm.NrMapped,
+			VMStatNrFilePages:                &vm.NrFilePages,
+			VMStatNrDirty:                    &vm.NrDirty,
+			VMStatNrWriteback:                &vm.NrWriteback,
+			VMStatNrShmem:                    &vm.NrShmem,
+			VMStatNrSlabReclaimable:          &vm.NrSlabReclaimable,
+			VMStatNrSlabUnreclaimable:        &vm.NrSlabUnreclaimable,
+			VMStatNrAnonTransparentHugePages: &vm.NrAnonTransparentHugePages,
+			VMStatNrDirtied:                  &vm.NrDirtied,
+			VMStatNrWritten:                  &vm.NrWritten,
+			VMStatPageIn:                     &vm.PageIn,
+			VMStatPageOut:                    &vm.PageOut,
+			VMStatSwapIn:                     &vm.SwapIn,
+			VMStatSwapOut:                    &vm.SwapOut,
+			VMStatPageFree:                   &vm.PageFree,
+			VMStatPageActivate:               &vm.PageActivate,
+			VMStatPageDeactivate:             &vm.PageDeactivate,
+			VMStatPageFault:                  &vm.PageFault,
+			VMStatPageMajorFault:             &vm.PageMajorFault,
+			VMStatPageRefill:                 &vm.PageRefill,
+			VMStatPageStealKswapd:            &vm.PageStealKswapd,
+			VMStatPageStealDirect:            &vm.PageStealDirect,
+			VMStatPageScanKswapd:             &vm.PageScanKswapd,
+			VMStatPageScanDirect:             &vm.PageScanDirect,
+			VMStatSlabsScanned:               &vm.SlabsScanned,
+			VMStatKswapdInodeSteal:           &vm.KswapdInodeSteal,
+			VMStatPageInodeSteal:             &vm.PageInodeSteal,
+			VMStatOOMKill:                    &vm.OOMKill,
+			VMStatCompactStall:               &vm.CompactStall,
+			VMStatCompactFail:                &vm.CompactFail,
+			VMStatCompactSuccess:             &vm.CompactSuccess,
+			VMStatTHPFaultAlloc:              &vm.THPFaultAlloc,
+			VMStatTHPFaultFallback:           &vm.THPFaultFallback,
+			VMStatTHPCollapseAlloc:           &vm.THPCollapseAlloc,
+			VMStatTHPCollapseAllocFailed:     &vm.THPCollapseAllocFailed,
+			VMStatTHPSplitPage:               &vm.THPSplitPage,
+			VMStatTHPSplitPageFailed:         &vm.THPSplitPageFailed,
+			VMStatTHPZeroPageAlloc:           &vm.THPZeroPageAlloc,
+			VMStatTHPSwpout:                  &vm.THPSwpout,
+		}
 	)
+	lines := bytes.Split(data, newline)
+	for _, line := range lines {
+		fields := bytes.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, errors.New("incorrectly formatted vmstat content")
+		}
+		value, err := strconv.ParseInt(FastBytesToString(fields[1]), 10, 64)
+		if nil != err {
+			return nil, err
+		}
+		key := FastBytesToString(fields[0])
+		if field, ok := known[key]; ok {
+			*field = value
+		} else {
+			vm.Others[string(fields[0])] = value
+		}
+	}
 	return vm, nil
 }
 
 func GetVmStat() (*VMStat, error) {
-	contents, err := os.ReadFile(VMStatFile)
+	return DefaultCollector.GetVmStat()
+}
+
+func (c *Collector) GetVmStat() (*VMStat, error) {
+	contents, err := c._ReadProcFile(VMStatFile)
 	if nil != err {
 		return nil, err
 	}
@@ -391,11 +609,62 @@ func _ParseStat(data []byte) (*Stat, error) {
 		} else {
 			switch key {
 			case StatSoftIRQ:
-				// Do Nothing
+				if len(fields) < 2 {
+					return nil, errors.New("incorrectly formatted stat content")
+				}
+				var (
+					softirq = &stat.SoftIRQs
+					columns = []*int64{
+						&softirq.Total,
+						&softirq.HI,
+						&softirq.Timer,
+						&softirq.NetTx,
+						&softirq.NetRx,
+						&softirq.Block,
+						&softirq.IRQPoll,
+						&softirq.Tasklet,
+						&softirq.Sched,
+						&softirq.HRTimer,
+						&softirq.RCU,
+					}
+				)
+				for i, field := range fields[1:] {
+					if i == len(columns) {
+						break
+					}
+					if v, err := strconv.ParseInt(FastBytesToString(field), 10, 64); nil != err {
+						return nil, err
+					} else {
+						*columns[i] = v
+					}
+				}
 			case StatInterrupts:
-				// Do Nothing
+				if len(fields) < 2 {
+					return nil, errors.New("incorrectly formatted stat content")
+				}
+				if v, err := strconv.ParseInt(FastBytesToString(fields[1]), 10, 64); nil != err {
+					return nil, err
+				} else {
+					stat.Interrupts = v
+				}
+				stat.InterruptCounts = make([]int64, 0, len(fields)-2)
+				for _, field := range fields[2:] {
+					if v, err := strconv.ParseInt(FastBytesToString(field), 10, 64); nil != err {
+						return nil, err
+					} else {
+						stat.InterruptCounts = append(stat.InterruptCounts, v)
+					}
+				}
 			case StatContextSwitches:
-				// Do Nothing
+				if len(fields) != 2 {
+					return nil, errors.New("incorrectly formatted stat content")
+				}
+				value := FastBytesToString(fields[1])
+				if v, err := strconv.ParseInt(value, 10, 64); nil != err {
+					return nil, err
+				} else {
+					stat.ContextSwitches = v
+				}
 			case StatBootTime:
 				if len(fields) != 2 {
 					return nil, errors.New("incorrectly formatted stat content")
@@ -445,7 +714,11 @@ func _ParseStat(data []byte) (*Stat, error) {
 }
 
 func GetStat() (*Stat, error) {
-	contents, err := os.ReadFile(StatFile)
+	return DefaultCollector.GetStat()
+}
+
+func (c *Collector) GetStat() (*Stat, error) {
+	contents, err := c._ReadProcFile(StatFile)
 	if nil != err {
 		return nil, err
 	}
@@ -475,11 +748,32 @@ func _ParseLoadAvg(data []byte) (*Load, error) {
 		Load5:  loads[1],
 		Load15: loads[2],
 	}
+	// The fourth field is runnable/total scheduling entities.
+	if len(fields) > 3 {
+		items := bytes.Split(fields[3], []byte("/"))
+		if len(items) != 2 {
+			return nil, errors.New("incorrectly formatted loadavg content")
+		}
+		if v, err := strconv.ParseInt(FastBytesToString(items[0]), 10, 64); nil != err {
+			return nil, err
+		} else {
+			load.Runnable = v
+		}
+		if v, err := strconv.ParseInt(FastBytesToString(items[1]), 10, 64); nil != err {
+			return nil, err
+		} else {
+			load.Entities = v
+		}
+	}
 	return load, nil
 }
 
 func GetLoadAvg() (*Load, error) {
-	contents, err := os.ReadFile(LoadAvgFile)
+	return DefaultCollector.GetLoadAvg()
+}
+
+func (c *Collector) GetLoadAvg() (*Load, error) {
+	contents, err := c._ReadProcFile(LoadAvgFile)
 	if nil != err {
 		return nil, err
 	}
@@ -504,6 +798,7 @@ func _ParseCPUInfo(data []byte) (*CPUInformation, error) {
 		CPUFrequency         = ""
 		CacheSize            = ""
 		CacheAlignment       = ""
+		Flags                = make([]string, 0)
 	)
 	lines := bytes.Split(data, newline)
 	for _, line := range lines {
@@ -521,9 +816,11 @@ func _ParseCPUInfo(data []byte) (*CPUInformation, error) {
 					CPUFrequency:   CPUFrequency,
 					CacheSize:      CacheSize,
 					CacheAlignment: CacheAlignment,
+					Flags:          Flags,
 				})
 			}
 			Id = -1
+			Flags = make([]string, 0)
 			continue
 		}
 		items := bytes.Split(line, colon)
@@ -569,6 +866,8 @@ func _ParseCPUInfo(data []byte) (*CPUInformation, error) {
 			CacheSize = value
 		case CPUInfoCacheAlignment:
 			CacheAlignment = value
+		case CPUInfoFlags:
+			Flags = strings.Fields(value)
 		default:
 			// Do Nothing
 		}
@@ -577,7 +876,11 @@ func _ParseCPUInfo(data []byte) (*CPUInformation, error) {
 }
 
 func GetCPUInfo() (*CPUInformation, error) {
-	contents, err := os.ReadFile(CPUInfoFile)
+	return DefaultCollector.GetCPUInfo()
+}
+
+func (c *Collector) GetCPUInfo() (*CPUInformation, error) {
+	contents, err := c._ReadProcFile(CPUInfoFile)
 	if nil != err {
 		return nil, err
 	}
@@ -610,7 +913,11 @@ func _ParseUptime(data []byte) (*Uptime, error) {
 }
 
 func GetUptime() (*Uptime, error) {
-	contents, err := os.ReadFile(UptimeFile)
+	return DefaultCollector.GetUptime()
+}
+
+func (c *Collector) GetUptime() (*Uptime, error) {
+	contents, err := c._ReadProcFile(UptimeFile)
 	if nil != err {
 		return nil, err
 	}
@@ -623,13 +930,6 @@ func _ParseNetworkStats(data []byte) (NetworkStats, error) {
 		colon   = []byte(":")
 		netstat = make(NetworkStats, 0)
 	)
-	var (
-		Interface          string = ""
-		ReceivedBytes      int64  = -1
-		ReceivedPackets    int64  = -1
-		TransmittedBytes   int64  = -1
-		TransmittedPackets int64  = -1
-	)
 	lines := bytes.Split(data, newline)
 	for i, line := range lines {
 		if i < 2 || len(line) == 0 {
@@ -642,51 +942,49 @@ func _ParseNetworkStats(data []byte) (NetworkStats, error) {
 		var (
 			key   = FastBytesToString(bytes.TrimSpace(items[0]))
 			value = bytes.Fields(bytes.TrimSpace(items[1]))
+			stat  = NetworkStat{Interface: key}
 		)
-		Interface = key
-		for i, elem := range value {
-			switch i {
-			case 0:
-				if v, err := strconv.ParseInt(FastBytesToString(elem), 10, 64); nil != err {
-					return nil, err
-				} else {
-					ReceivedBytes = v
-				}
-			case 1:
-				if v, err := strconv.ParseInt(FastBytesToString(elem), 10, 64); nil != err {
-					return nil, err
-				} else {
-					ReceivedPackets = v
-				}
-			case 8:
-				if v, err := strconv.ParseInt(FastBytesToString(elem), 10, 64); nil != err {
-					return nil, err
-				} else {
-					TransmittedBytes = v
-				}
-			case 9:
-				if v, err := strconv.ParseInt(FastBytesToString(elem), 10, 64); nil != err {
-					return nil, err
-				} else {
-					TransmittedPackets = v
-				}
-			default:
-				// Do Nothing
+		columns := []*int64{
+			&stat.ReceivedBytes,
+			&stat.ReceivedPackets,
+			&stat.ReceivedErrors,
+			&stat.ReceivedDropped,
+			&stat.ReceivedFIFO,
+			&stat.ReceivedFrame,
+			&stat.ReceivedCompressed,
+			&stat.ReceivedMulticast,
+			&stat.TransmittedBytes,
+			&stat.TransmittedPackets,
+			&stat.TransmittedErrors,
+			&stat.TransmittedDropped,
+			&stat.TransmittedFIFO,
+			&stat.TransmittedCollisions,
+			&stat.TransmittedCarrier,
+			&stat.TransmittedCompressed,
+		}
+		if len(value) < len(columns) {
+			return nil, errors.New("incorrectly formatted net content")
+		}
+		// Counters are unsigned longs, values past math.MaxInt64 wrap
+		// negative and are recovered by CalculateNetworkRates.
+		for i, column := range columns {
+			if v, err := strconv.ParseUint(FastBytesToString(value[i]), 10, 64); nil != err {
+				return nil, err
+			} else {
+				*column = int64(v)
 			}
 		}
-		netstat = append(netstat, NetworkStat{
-			Interface:          Interface,
-			ReceivedBytes:      ReceivedBytes,
-			ReceivedPackets:    ReceivedPackets,
-			TransmittedBytes:   TransmittedBytes,
-			TransmittedPackets: TransmittedPackets,
-		})
+		netstat = append(netstat, stat)
 	}
 	return netstat, nil
 }
 
 func GetNetworkStats() (NetworkStats, error) {
-	contents, err := os.ReadFile(NetworkStatFile)
+	return DefaultCollector.GetNetworkStats()
+}
+
+func (c *Collector) GetNetworkStats() (NetworkStats, error) {
+	contents, err := c._ReadProcFile(NetworkStatFile)
 	if nil != err {
 		return nil, err
 	}
@@ -694,17 +992,20 @@ func GetNetworkStats() (NetworkStats, error) {
 }
 
 func ListProcessId() ([]int, error) {
-	directory, err := os.Open(ProcDirectory)
-	if nil != err {
-		return nil, err
-	}
-	children, err := directory.Readdirnames(0)
+	return DefaultCollector.ListProcessId()
+}
+
+func (c *Collector) ListProcessId() ([]int, error) {
+	children, err := fs.ReadDir(c._FS(), c._ProcPath())
 	if nil != err {
 		return nil, err
 	}
 	processes := make([]int, 0)
 	for _, child := range children {
-		if pid, err := strconv.Atoi(child); err == nil {
+		if !child.IsDir() {
+			continue
+		}
+		if pid, err := strconv.Atoi(child.Name()); err == nil {
 			processes = append(processes, pid)
 		}
 	}
@@ -792,6 +1093,9 @@ func _ParseDiskStats(data []byte) (DiskStats, error) {
 	)
 	for _, line := range lines {
 		fields := bytes.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
 		for i, field := range fields {
 			switch i {
 			case 0:
@@ -927,7 +1231,11 @@ func _ParseDiskStats(data []byte) (DiskStats, error) {
 }
 
 func GetDiskStats() (DiskStats, error) {
-	contents, err := os.ReadFile(DiskStatFile)
+	return DefaultCollector.GetDiskStats()
+}
+
+func (c *Collector) GetDiskStats() (DiskStats, error) {
+	contents, err := c._ReadProcFile(DiskStatFile)
 	if nil != err {
 		return nil, err
 	}
@@ -942,11 +1250,38 @@ func GetFileSystemStat(path string) (*FileSystemStat, error) {
 	if err != nil {
 		return nil, err
 	}
+	// Block counts are in units of the fragment size, which very old
+	// kernels leave unset.
+	size := int64(stat.Frsize)
+	if size == 0 {
+		size = int64(stat.Bsize)
+	}
+	var (
+		magic = int64(uint32(stat.Type))
+		flags = int64(stat.Flags)
+	)
 	fs := &FileSystemStat{
-		Available: stat.Bsize * int64(stat.Bavail),
-		Free:      stat.Bsize * int64(stat.Bfree),
-		Capacity:  stat.Bsize * int64(stat.Blocks),
-		Files:     int64(stat.Files),
+		Type:       FileSystemMagics[magic],
+		Magic:      magic,
+		BlockSize:  size,
+		Available:  size * int64(stat.Bavail),
+		Free:       size * int64(stat.Bfree),
+		Capacity:   size * int64(stat.Blocks),
+		Files:      int64(stat.Files),
+		FreeFiles:  int64(stat.Ffree),
+		NameLength: int64(stat.Namelen),
+		Flags:      flags,
+		MountFlags: make([]string, 0),
+	}
+	fs.Used = fs.Capacity - fs.Free
+	fs.UsedFiles = fs.Files - fs.FreeFiles
+	// Like df, the percentage is of the space available to unprivileged
+	// users, leaving out the blocks reserved for root.
+	fs.UsedPercent = _Percentage(fs.Used, fs.Used+fs.Available)
+	fs.UsedFilesPercent = _Percentage(fs.UsedFiles, fs.Files)
+	if flags&MountFlagValid != 0 {
+		fs.ReadOnly = flags&MountFlagReadOnly != 0
+		fs.MountFlags = _MountFlags(flags)
 	}
 	return fs, nil
 }
@@ -0,0 +1,401 @@
+package sysinfo_go
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	ProcessCgroupFile = "cgroup"
+)
+
+const (
+	CgroupV1FileSystem = "cgroup"
+	CgroupV2FileSystem = "cgroup2"
+)
+
+const (
+	CgroupUnifiedDirectory = "/sys/fs/cgroup/unified"
+	CgroupControllersFile  = "cgroup.controllers"
+)
+
+const (
+	CgroupCPUStatFile       = "cpu.stat"
+	CgroupCPUMaxFile        = "cpu.max"
+	CgroupMemoryCurrentFile = "memory.current"
+	CgroupMemoryMaxFile     = "memory.max"
+	CgroupMemoryStatFile    = "memory.stat"
+	CgroupMemoryEventsFile  = "memory.events"
+	CgroupIOStatFile        = "io.stat"
+	CgroupPidsCurrentFile   = "pids.current"
+	CgroupPidsMaxFile       = "pids.max"
+)
+
+// CgroupUnlimited is reported for limits set to "max".
+const CgroupUnlimited = -1
+
+// _ParseKeyValues parses flat keyed files such as cpu.stat and memory.stat
+// with one "key value" pair per line.
+func _ParseKeyValues(data []byte) (map[string]int64, error) {
+	var (
+		newline = []byte("\n")
+		values  = make(map[string]int64)
+	)
+	lines := bytes.Split(data, newline)
+	for _, line := range lines {
+		fields := bytes.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, errors.New("incorrectly formatted key value content")
+		}
+		if v, err := strconv.ParseInt(FastBytesToString(fields[1]), 10, 64); nil != err {
+			return nil, err
+		} else {
+			values[string(fields[0])] = v
+		}
+	}
+	return values, nil
+}
+
+// _ParseCgroupValue parses single value files, mapping "max" onto
+// CgroupUnlimited.
+func _ParseCgroupValue(data []byte) (int64, error) {
+	value := FastBytesToString(bytes.TrimSpace(data))
+	if value == "max" {
+		return CgroupUnlimited, nil
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
+
+func _ParseCgroupCPUMax(data []byte) (*CgroupCPUMax, error) {
+	fields := bytes.Fields(data)
+	if len(fields) != 2 {
+		return nil, errors.New("incorrectly formatted cpu.max content")
+	}
+	quota, err := _ParseCgroupValue(fields[0])
+	if nil != err {
+		return nil, err
+	}
+	period, err := strconv.ParseInt(FastBytesToString(fields[1]), 10, 64)
+	if nil != err {
+		return nil, err
+	}
+	return &CgroupCPUMax{Quota: quota, Period: period}, nil
+}
+
+func _ParseCgroupIOStat(data []byte) ([]CgroupIOStat, error) {
+	var (
+		newline = []byte("\n")
+		equal   = []byte("=")
+		stats   = make([]CgroupIOStat, 0)
+	)
+	lines := bytes.Split(data, newline)
+	for _, line := range lines {
+		fields := bytes.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		device := bytes.Split(fields[0], []byte(":"))
+		if len(device) != 2 {
+			return nil, errors.New("incorrectly formatted io.stat content")
+		}
+		stat := CgroupIOStat{}
+		if v, err := strconv.ParseInt(FastBytesToString(device[0]), 10, 64); nil != err {
+			return nil, err
+		} else {
+			stat.Major = v
+		}
+		if v, err := strconv.ParseInt(FastBytesToString(device[1]), 10, 64); nil != err {
+			return nil, err
+		} else {
+			stat.Minor = v
+		}
+		// Keys of io.latency and io.cost, such as depth=max and the
+		// fractional cost.vrate, are not counters and are skipped unparsed.
+		known := map[string]*int64{
+			"rbytes": &stat.ReadBytes,
+			"wbytes": &stat.WriteBytes,
+			"rios":   &stat.ReadIOs,
+			"wios":   &stat.WriteIOs,
+			"dbytes": &stat.DiscardBytes,
+			"dios":   &stat.DiscardIOs,
+		}
+		for _, field := range fields[1:] {
+			items := bytes.Split(field, equal)
+			if len(items) != 2 {
+				return nil, errors.New("incorrectly formatted io.stat content")
+			}
+			value, ok := known[FastBytesToString(items[0])]
+			if !ok {
+				continue
+			}
+			v, err := strconv.ParseInt(FastBytesToString(items[1]), 10, 64)
+			if nil != err {
+				return nil, err
+			}
+			*value = v
+		}
+		stats = append(stats, stat)
+	}
+	return stats, nil
+}
+
+func _ParseProcessCgroups(data []byte) (ProcessCgroups, error) {
+	var (
+		newline = []byte("\n")
+		colon   = []byte(":")
+		cgroups = make(ProcessCgroups, 0)
+	)
+	lines := bytes.Split(data, newline)
+	for _, line := range lines {
+		if len(line) == 0 {
+			continue
+		}
+		items := bytes.SplitN(line, colon, 3)
+		if len(items) != 3 {
+			return nil, errors.New("incorrectly formatted cgroup content")
+		}
+		cgroup := ProcessCgroup{
+			Controllers: make([]string, 0),
+			Path:        string(items[2]),
+		}
+		if v, err := strconv.ParseInt(FastBytesToString(items[0]), 10, 64); nil != err {
+			return nil, err
+		} else {
+			cgroup.HierarchyId = v
+		}
+		if len(items[1]) > 0 {
+			cgroup.Controllers = strings.Split(string(items[1]), ",")
+		}
+		cgroups = append(cgroups, cgroup)
+	}
+	return cgroups, nil
+}
+
+func GetProcessCgroups(pid int) (ProcessCgroups, error) {
+	return DefaultCollector.GetProcessCgroups(pid)
+}
+
+// GetProcessCgroups returns the cgroup memberships of a process, one entry
+// per hierarchy. The cgroup v2 membership has hierarchy 0 and no
+// controllers.
+func (c *Collector) GetProcessCgroups(pid int) (ProcessCgroups, error) {
+	return c._GetProcessCgroups(strconv.Itoa(pid))
+}
+
+// _GetProcessCgroups takes the /proc directory of the process, which is
+// "self" for the calling process.
+func (c *Collector) _GetProcessCgroups(directory string) (ProcessCgroups, error) {
+	contents, err := c._ReadProcFile(directory, ProcessCgroupFile)
+	if nil != err {
+		return nil, _ProcessError(err)
+	}
+	return _ParseProcessCgroups(contents)
+}
+
+// _CgroupV2Directory returns the cgroup2 mount, which hybrid hierarchies
+// keep under /sys/fs/cgroup/unified.
+func (c *Collector) _CgroupV2Directory() string {
+	if _, err := fs.Stat(c._FS(), c._SysPath(CgroupDirectory, CgroupControllersFile)); nil == err {
+		return CgroupDirectory
+	}
+	if _, err := fs.Stat(c._FS(), c._SysPath(CgroupUnifiedDirectory, CgroupControllersFile)); nil == err {
+		return CgroupUnifiedDirectory
+	}
+	return CgroupDirectory
+}
+
+// _CgroupMountGroup returns the group at path relative to a cgroup mount
+// whose mountinfo Root is root, as when a hierarchy is mounted from inside a
+// group in a container. Groups outside the mounted tree are not visible
+// through the mount.
+func _CgroupMountGroup(root, group string) (string, bool) {
+	switch {
+	case root == "/" || root == group:
+		return path.Join("/", strings.TrimPrefix(group, root)), true
+	case strings.HasPrefix(group, root+"/"):
+		return strings.TrimPrefix(group, root), true
+	default:
+		return "", false
+	}
+}
+
+// _CgroupV2Root returns the cgroup2 mount and its mountinfo Root. With
+// cgroupns=host, or a cgroup2 mount bind-mounted from inside a group, the
+// Root is that group rather than "/".
+func (c *Collector) _CgroupV2Root() (string, string) {
+	directory := c._CgroupV2Directory()
+	mounts, err := c.GetMounts()
+	if nil != err {
+		return directory, "/"
+	}
+	for _, mount := range mounts {
+		if mount.FileSystemType == CgroupV2FileSystem && mount.MountPoint == directory {
+			return directory, mount.Root
+		}
+	}
+	return directory, "/"
+}
+
+// _CgroupV2Group returns the cgroup2 mount and the group, as listed in
+// /proc/[pid]/cgroup, relative to it. Groups outside the mounted tree are
+// not visible.
+func (c *Collector) _CgroupV2Group(group string) (string, string, bool) {
+	directory, root := c._CgroupV2Root()
+	group, ok := _CgroupMountGroup(root, path.Clean("/"+group))
+	return directory, group, ok
+}
+
+func GetCgroup(path string) (*Cgroup, error) {
+	return DefaultCollector.GetCgroup(path)
+}
+
+// GetCgroup reads the cgroup v2 group at path, as listed in
+// /proc/[pid]/cgroup. Files of controllers that are not enabled for the
+// group are skipped and leave their fields zero or nil. CPU times are in
+// microseconds.
+func (c *Collector) GetCgroup(path string) (*Cgroup, error) {
+	var (
+		directory = c._CgroupV2Directory()
+		cgroup    = &Cgroup{
+			Path:        path,
+			MemoryMax:   CgroupUnlimited,
+			PidsMax:     CgroupUnlimited,
+			MemoryStat:  make(map[string]int64),
+			IOStats:     make([]CgroupIOStat, 0),
+			Controllers: make([]string, 0),
+		}
+	)
+	read := func(name string) ([]byte, bool, error) {
+		contents, err := c._ReadSysFile(directory, path, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, false, nil
+		}
+		return contents, nil == err, err
+	}
+	contents, ok, err := read(CgroupControllersFile)
+	if nil != err {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("cgroup v2 group not found")
+	}
+	cgroup.Controllers = append(cgroup.Controllers, strings.Fields(string(contents))...)
+	if contents, ok, err := read(CgroupCPUStatFile); nil != err {
+		return nil, err
+	} else if ok {
+		values, err := _ParseKeyValues(contents)
+		if nil != err {
+			return nil, err
+		}
+		cgroup.CPUStat = &CgroupCPUStat{
+			Usage:         values["usage_usec"],
+			User:          values["user_usec"],
+			System:        values["system_usec"],
+			Periods:       values["nr_periods"],
+			Throttled:     values["nr_throttled"],
+			ThrottledTime: values["throttled_usec"],
+		}
+	}
+	if contents, ok, err := read(CgroupCPUMaxFile); nil != err {
+		return nil, err
+	} else if ok {
+		if cgroup.CPUMax, err = _ParseCgroupCPUMax(contents); nil != err {
+			return nil, err
+		}
+	}
+	if contents, ok, err := read(CgroupMemoryCurrentFile); nil != err {
+		return nil, err
+	} else if ok {
+		if cgroup.MemoryCurrent, err = _ParseCgroupValue(contents); nil != err {
+			return nil, err
+		}
+	}
+	if contents, ok, err := read(CgroupMemoryMaxFile); nil != err {
+		return nil, err
+	} else if ok {
+		if cgroup.MemoryMax, err = _ParseCgroupValue(contents); nil != err {
+			return nil, err
+		}
+	}
+	if contents, ok, err := read(CgroupMemoryStatFile); nil != err {
+		return nil, err
+	} else if ok {
+		if cgroup.MemoryStat, err = _ParseKeyValues(contents); nil != err {
+			return nil, err
+		}
+	}
+	if contents, ok, err := read(CgroupMemoryEventsFile); nil != err {
+		return nil, err
+	} else if ok {
+		values, err := _ParseKeyValues(contents)
+		if nil != err {
+			return nil, err
+		}
+		cgroup.MemoryEvents = &CgroupMemoryEvents{
+			Low:          values["low"],
+			High:         values["high"],
+			Max:          values["max"],
+			OOM:          values["oom"],
+			OOMKill:      values["oom_kill"],
+			OOMGroupKill: values["oom_group_kill"],
+		}
+	}
+	if contents, ok, err := read(CgroupIOStatFile); nil != err {
+		return nil, err
+	} else if ok {
+		if cgroup.IOStats, err = _ParseCgroupIOStat(contents); nil != err {
+			return nil, err
+		}
+	}
+	if contents, ok, err := read(CgroupPidsCurrentFile); nil != err {
+		return nil, err
+	} else if ok {
+		if cgroup.PidsCurrent, err = _ParseCgroupValue(contents); nil != err {
+			return nil, err
+		}
+	}
+	if contents, ok, err := read(CgroupPidsMaxFile); nil != err {
+		return nil, err
+	} else if ok {
+		if cgroup.PidsMax, err = _ParseCgroupValue(contents); nil != err {
+			return nil, err
+		}
+	}
+	return cgroup, nil
+}
+
+func GetProcessCgroup(pid int) (*Cgroup, error) {
+	return DefaultCollector.GetProcessCgroup(pid)
+}
+
+// GetProcessCgroup reads the cgroup v2 group a process belongs to. Path
+// keeps the group as listed in /proc/[pid]/cgroup.
+func (c *Collector) GetProcessCgroup(pid int) (*Cgroup, error) {
+	cgroups, err := c.GetProcessCgroups(pid)
+	if nil != err {
+		return nil, err
+	}
+	for _, cgroup := range cgroups {
+		if cgroup.HierarchyId != 0 || len(cgroup.Controllers) != 0 {
+			continue
+		}
+		_, group, ok := c._CgroupV2Group(cgroup.Path)
+		if !ok {
+			return nil, errors.New("cgroup v2 group not visible through the mount")
+		}
+		result, err := c.GetCgroup(group)
+		if nil != err {
+			return nil, err
+		}
+		result.Path = cgroup.Path
+		return result, nil
+	}
+	return nil, errors.New("process has no cgroup v2 membership")
+}
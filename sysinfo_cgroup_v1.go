@@ -0,0 +1,323 @@
+package sysinfo_go
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	CgroupModeNone   = "none"
+	CgroupModeV1     = "v1"
+	CgroupModeV2     = "v2"
+	CgroupModeHybrid = "hybrid"
+)
+
+const (
+	CgroupV1ControllerCPU     = "cpu"
+	CgroupV1ControllerCPUAcct = "cpuacct"
+	CgroupV1ControllerMemory  = "memory"
+	CgroupV1ControllerBlkio   = "blkio"
+)
+
+const (
+	CgroupV1CPUAcctUsageFile      = "cpuacct.usage"
+	CgroupV1CPUQuotaFile          = "cpu.cfs_quota_us"
+	CgroupV1CPUPeriodFile         = "cpu.cfs_period_us"
+	CgroupV1MemoryUsageFile       = "memory.usage_in_bytes"
+	CgroupV1MemoryLimitFile       = "memory.limit_in_bytes"
+	CgroupV1MemoryStatFile        = "memory.stat"
+	CgroupV1BlkioServiceBytesFile = "blkio.throttle.io_service_bytes"
+	CgroupV1BlkioServicedFile     = "blkio.throttle.io_serviced"
+)
+
+var _CgroupV1Controllers = map[string]bool{
+	"blkio":      true,
+	"cpu":        true,
+	"cpuacct":    true,
+	"cpuset":     true,
+	"devices":    true,
+	"freezer":    true,
+	"hugetlb":    true,
+	"memory":     true,
+	"misc":       true,
+	"net_cls":    true,
+	"net_prio":   true,
+	"perf_event": true,
+	"pids":       true,
+	"rdma":       true,
+}
+
+// _CgroupV1MemoryUnlimited is the smallest memory.limit_in_bytes treated as
+// unlimited; the kernel reports the page counter maximum rounded down to the
+// page size, 9223372036854771712 with 4k pages.
+const _CgroupV1MemoryUnlimited = 1 << 62
+
+func _CgroupMode(mounts Mounts) string {
+	var (
+		v1 = false
+		v2 = false
+	)
+	for _, mount := range mounts {
+		switch mount.FileSystemType {
+		case CgroupV1FileSystem:
+			v1 = true
+		case CgroupV2FileSystem:
+			v2 = true
+		default:
+			// Do Nothing
+		}
+	}
+	switch {
+	case v1 && v2:
+		return CgroupModeHybrid
+	case v1:
+		return CgroupModeV1
+	case v2:
+		return CgroupModeV2
+	default:
+		return CgroupModeNone
+	}
+}
+
+func GetCgroupMode() (string, error) {
+	return DefaultCollector.GetCgroupMode()
+}
+
+// GetCgroupMode tells from the mounted cgroup filesystems whether the host
+// uses legacy v1 hierarchies, the v2 unified hierarchy, or both.
+func (c *Collector) GetCgroupMode() (string, error) {
+	mounts, err := c.GetMounts()
+	if nil != err {
+		return "", err
+	}
+	return _CgroupMode(mounts), nil
+}
+
+// _MountPath maps an absolute host path taken from mountinfo onto the
+// collector, below SysRoot when it lies in /sys.
+func (c *Collector) _MountPath(name string, elem ...string) string {
+	if name == SysDirectory || strings.HasPrefix(name, SysDirectory+"/") {
+		return c._SysPath(append([]string{name}, elem...)...)
+	}
+	return _Resolve(name, "/", elem...)
+}
+
+// _ReadCgroupFile reads a file of a cgroup directory, reporting whether it
+// exists.
+func (c *Collector) _ReadCgroupFile(directory, name string) ([]byte, bool, error) {
+	contents, err := fs.ReadFile(c._FS(), c._MountPath(directory, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	return contents, nil == err, err
+}
+
+// _CgroupV1Directories maps every v1 controller onto the directory of the
+// group at the given per hierarchy paths, accounting for the mount root of
+// hierarchies mounted from inside a group, as in containers.
+func _CgroupV1Directories(mounts Mounts, cgroups ProcessCgroups) map[string]string {
+	directories := make(map[string]string)
+	for _, mount := range mounts {
+		if mount.FileSystemType != CgroupV1FileSystem {
+			continue
+		}
+		for _, option := range mount.SuperOptions {
+			if !_CgroupV1Controllers[option] {
+				continue
+			}
+			for _, cgroup := range cgroups {
+				for _, controller := range cgroup.Controllers {
+					if controller != option {
+						continue
+					}
+					if group, ok := _CgroupMountGroup(mount.Root, cgroup.Path); ok {
+						directories[controller] = path.Join(mount.MountPoint, group)
+					}
+				}
+			}
+		}
+	}
+	return directories
+}
+
+func _ParseCgroupV1Blkio(data []byte) ([]CgroupV1BlkioStat, error) {
+	var (
+		newline = []byte("\n")
+		stats   = make([]CgroupV1BlkioStat, 0)
+		devices = make(map[string]int)
+	)
+	lines := bytes.Split(data, newline)
+	for _, line := range lines {
+		fields := bytes.Fields(line)
+		if len(fields) == 0 || len(fields) == 2 {
+			// Skip the trailing "Total" line summing every device.
+			continue
+		}
+		if len(fields) != 3 {
+			return nil, errors.New("incorrectly formatted blkio content")
+		}
+		device := string(fields[0])
+		index, ok := devices[device]
+		if !ok {
+			items := strings.Split(device, ":")
+			if len(items) != 2 {
+				return nil, errors.New("incorrectly formatted blkio content")
+			}
+			stat := CgroupV1BlkioStat{}
+			if v, err := strconv.ParseInt(items[0], 10, 64); nil != err {
+				return nil, err
+			} else {
+				stat.Major = v
+			}
+			if v, err := strconv.ParseInt(items[1], 10, 64); nil != err {
+				return nil, err
+			} else {
+				stat.Minor = v
+			}
+			index = len(stats)
+			devices[device] = index
+			stats = append(stats, stat)
+		}
+		v, err := strconv.ParseInt(FastBytesToString(fields[2]), 10, 64)
+		if nil != err {
+			return nil, err
+		}
+		stat := &stats[index]
+		switch FastBytesToString(fields[1]) {
+		case "Read":
+			stat.Read = v
+		case "Write":
+			stat.Write = v
+		case "Sync":
+			stat.Sync = v
+		case "Async":
+			stat.Async = v
+		case "Discard":
+			stat.Discard = v
+		case "Total":
+			stat.Total = v
+		default:
+			// Do Nothing
+		}
+	}
+	return stats, nil
+}
+
+func (c *Collector) _GetCgroupV1(directories map[string]string) (*CgroupV1, error) {
+	cgroup := &CgroupV1{
+		Directories:  directories,
+		CPUQuota:     CgroupUnlimited,
+		MemoryLimit:  CgroupUnlimited,
+		MemoryStat:   make(map[string]int64),
+		ServiceBytes: make([]CgroupV1BlkioStat, 0),
+		Serviced:     make([]CgroupV1BlkioStat, 0),
+	}
+	read := func(controller, name string) ([]byte, bool, error) {
+		directory, ok := directories[controller]
+		if !ok {
+			return nil, false, nil
+		}
+		return c._ReadCgroupFile(directory, name)
+	}
+	value := func(controller, name string, field *int64) error {
+		contents, ok, err := read(controller, name)
+		if nil != err || !ok {
+			return err
+		}
+		v, err := strconv.ParseInt(FastBytesToString(bytes.TrimSpace(contents)), 10, 64)
+		if nil != err {
+			return err
+		}
+		*field = v
+		return nil
+	}
+	if err := value(CgroupV1ControllerCPUAcct, CgroupV1CPUAcctUsageFile, &cgroup.CPUUsage); nil != err {
+		return nil, err
+	}
+	if err := value(CgroupV1ControllerCPU, CgroupV1CPUQuotaFile, &cgroup.CPUQuota); nil != err {
+		return nil, err
+	}
+	if err := value(CgroupV1ControllerCPU, CgroupV1CPUPeriodFile, &cgroup.CPUPeriod); nil != err {
+		return nil, err
+	}
+	if err := value(CgroupV1ControllerMemory, CgroupV1MemoryUsageFile, &cgroup.MemoryUsage); nil != err {
+		return nil, err
+	}
+	if err := value(CgroupV1ControllerMemory, CgroupV1MemoryLimitFile, &cgroup.MemoryLimit); nil != err {
+		return nil, err
+	}
+	if cgroup.CPUQuota < 0 {
+		cgroup.CPUQuota = CgroupUnlimited
+	}
+	if cgroup.MemoryLimit >= _CgroupV1MemoryUnlimited {
+		cgroup.MemoryLimit = CgroupUnlimited
+	}
+	if contents, ok, err := read(CgroupV1ControllerMemory, CgroupV1MemoryStatFile); nil != err {
+		return nil, err
+	} else if ok {
+		if cgroup.MemoryStat, err = _ParseKeyValues(contents); nil != err {
+			return nil, err
+		}
+	}
+	if contents, ok, err := read(CgroupV1ControllerBlkio, CgroupV1BlkioServiceBytesFile); nil != err {
+		return nil, err
+	} else if ok {
+		if cgroup.ServiceBytes, err = _ParseCgroupV1Blkio(contents); nil != err {
+			return nil, err
+		}
+	}
+	if contents, ok, err := read(CgroupV1ControllerBlkio, CgroupV1BlkioServicedFile); nil != err {
+		return nil, err
+	} else if ok {
+		if cgroup.Serviced, err = _ParseCgroupV1Blkio(contents); nil != err {
+			return nil, err
+		}
+	}
+	return cgroup, nil
+}
+
+func GetCgroupV1(path string) (*CgroupV1, error) {
+	return DefaultCollector.GetCgroupV1(path)
+}
+
+// GetCgroupV1 reads the group at the same path in every mounted v1
+// hierarchy. CPU usage is in nanoseconds and CPU quota and period in
+// microseconds.
+func (c *Collector) GetCgroupV1(path string) (*CgroupV1, error) {
+	mounts, err := c.GetMounts()
+	if nil != err {
+		return nil, err
+	}
+	cgroups := make(ProcessCgroups, 0)
+	for _, mount := range mounts {
+		if mount.FileSystemType == CgroupV1FileSystem {
+			cgroups = append(cgroups, ProcessCgroup{
+				Controllers: mount.SuperOptions,
+				Path:        path,
+			})
+		}
+	}
+	return c._GetCgroupV1(_CgroupV1Directories(mounts, cgroups))
+}
+
+func GetProcessCgroupV1(pid int) (*CgroupV1, error) {
+	return DefaultCollector.GetProcessCgroupV1(pid)
+}
+
+// GetProcessCgroupV1 reads the v1 groups a process belongs to in each
+// hierarchy.
+func (c *Collector) GetProcessCgroupV1(pid int) (*CgroupV1, error) {
+	cgroups, err := c.GetProcessCgroups(pid)
+	if nil != err {
+		return nil, err
+	}
+	mounts, err := c.GetMounts()
+	if nil != err {
+		return nil, err
+	}
+	return c._GetCgroupV1(_CgroupV1Directories(mounts, cgroups))
+}
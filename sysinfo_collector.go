@@ -0,0 +1,104 @@
+package sysinfo_go
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+)
+
+const (
+	SysDirectory = "/sys"
+)
+
+// Collector reads procfs and sysfs through FS, resolving every file below
+// ProcRoot and SysRoot. The zero value reads the live /proc and /sys of the
+// running host.
+type Collector struct {
+	ProcRoot string
+	SysRoot  string
+	FS       fs.FS
+}
+
+// DefaultCollector backs the package level Get* functions.
+var DefaultCollector = NewCollector(nil, ProcDirectory, SysDirectory)
+
+// NewCollector returns a collector reading procRoot and sysRoot from fsys.
+// A nil fsys reads the host root filesystem, so procRoot "/host/proc" reads
+// a host /proc mounted inside a container, while os.DirFS("snapshot") with
+// procRoot "proc" reads a captured copy.
+func NewCollector(fsys fs.FS, procRoot, sysRoot string) *Collector {
+	return &Collector{
+		ProcRoot: procRoot,
+		SysRoot:  sysRoot,
+		FS:       fsys,
+	}
+}
+
+func (c *Collector) _FS() fs.FS {
+	if nil == c.FS {
+		return os.DirFS("/")
+	}
+	return c.FS
+}
+
+func _Resolve(root, fallback string, elem ...string) string {
+	if len(root) == 0 {
+		root = fallback
+	}
+	name := path.Join(append([]string{root}, elem...)...)
+	name = strings.TrimPrefix(name, "/")
+	if len(name) == 0 {
+		return "."
+	}
+	return name
+}
+
+// _TrimRoot strips root from the first element when it names root or a path
+// below it, copying elem so the caller's slice is left alone.
+func _TrimRoot(root string, elem []string) []string {
+	if len(elem) == 0 {
+		return elem
+	}
+	elem = append([]string(nil), elem...)
+	if elem[0] == root || strings.HasPrefix(elem[0], root+"/") {
+		elem[0] = strings.TrimPrefix(elem[0], root)
+	}
+	return elem
+}
+
+// _ProcPath maps a path under /proc, such as MemInfoFile, or a path relative
+// to the proc root onto the collector's proc root.
+func (c *Collector) _ProcPath(elem ...string) string {
+	return _Resolve(c.ProcRoot, ProcDirectory, _TrimRoot(ProcDirectory, elem)...)
+}
+
+// _SysPath maps a path under /sys, or a path relative to the sys root onto
+// the collector's sys root.
+func (c *Collector) _SysPath(elem ...string) string {
+	return _Resolve(c.SysRoot, SysDirectory, _TrimRoot(SysDirectory, elem)...)
+}
+
+func (c *Collector) _ReadProcFile(elem ...string) ([]byte, error) {
+	return fs.ReadFile(c._FS(), c._ProcPath(elem...))
+}
+
+func (c *Collector) _ReadSysFile(elem ...string) ([]byte, error) {
+	return fs.ReadFile(c._FS(), c._SysPath(elem...))
+}
+
+// _ReadLink resolves a symbolic link such as /proc/[pid]/exe. fs.FS has no
+// readlink, so a custom FS may provide a ReadLink(name) method; otherwise
+// links can only be read from the host filesystem.
+func (c *Collector) _ReadLink(name string) (string, error) {
+	if nil == c.FS {
+		return os.Readlink("/" + name)
+	}
+	if fsys, ok := c.FS.(interface {
+		ReadLink(name string) (string, error)
+	}); ok {
+		return fsys.ReadLink(name)
+	}
+	return "", &fs.PathError{Op: "readlink", Path: name, Err: errors.New("readlink not supported")}
+}
@@ -0,0 +1,134 @@
+package sysinfo_go
+
+import (
+	"sync"
+	"time"
+)
+
+// CPUSampler reports CPU utilisation over the interval between successive
+// reads of /proc/stat.
+type CPUSampler struct {
+	collector *Collector
+	mutex     sync.Mutex
+	previous  *Stat
+	time      time.Time
+}
+
+func NewCPUSampler(collector *Collector) *CPUSampler {
+	if nil == collector {
+		collector = DefaultCollector
+	}
+	return &CPUSampler{
+		collector: collector,
+	}
+}
+
+// Sample reads /proc/stat and returns the utilisation since the previous
+// call. The first call reports the average since boot.
+func (s *CPUSampler) Sample() (*CPUUsage, error) {
+	stat, err := s.collector.GetStat()
+	if nil != err {
+		return nil, err
+	}
+	now := time.Now()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	previous := s.previous
+	if nil == previous {
+		previous = new(Stat)
+	}
+	usage := CalculateCPUUsage(previous, stat)
+	if !s.time.IsZero() {
+		usage.Interval = now.Sub(s.time)
+	}
+	s.previous, s.time = stat, now
+	return usage, nil
+}
+
+func _CPUStatTotal(stat *CPUStat) int64 {
+	// Guest time is already accounted in user time.
+	return stat.User + stat.Nice + stat.System + stat.Idle + stat.IOWait +
+		stat.IRQ + stat.SoftIRQ + stat.Steal
+}
+
+// _CounterDelta clamps counters going backwards, which iowait is known to
+// do, to zero.
+func _CounterDelta(previous, current int64) int64 {
+	if current < previous {
+		return 0
+	}
+	return current - previous
+}
+
+func _CPUStatDelta(previous, current *CPUStat) (CPUUtilisation, bool) {
+	// A CPU whose counters went backwards as a whole was reset, for example
+	// by being taken offline and brought back, and has no usable interval.
+	if _CPUStatTotal(current) < _CPUStatTotal(previous) {
+		return CPUUtilisation{CPUId: current.CPUId}, false
+	}
+	delta := CPUStat{
+		User:      _CounterDelta(previous.User, current.User),
+		Nice:      _CounterDelta(previous.Nice, current.Nice),
+		System:    _CounterDelta(previous.System, current.System),
+		Idle:      _CounterDelta(previous.Idle, current.Idle),
+		IOWait:    _CounterDelta(previous.IOWait, current.IOWait),
+		IRQ:       _CounterDelta(previous.IRQ, current.IRQ),
+		SoftIRQ:   _CounterDelta(previous.SoftIRQ, current.SoftIRQ),
+		Steal:     _CounterDelta(previous.Steal, current.Steal),
+		Guest:     _CounterDelta(previous.Guest, current.Guest),
+		GuestNice: _CounterDelta(previous.GuestNice, current.GuestNice),
+	}
+	total := _CPUStatTotal(&delta)
+	if total <= 0 {
+		return CPUUtilisation{CPUId: current.CPUId}, false
+	}
+	percent := func(value int64) float64 {
+		return float64(value) / float64(total) * 100
+	}
+	return CPUUtilisation{
+		CPUId:     current.CPUId,
+		User:      percent(delta.User),
+		Nice:      percent(delta.Nice),
+		System:    percent(delta.System),
+		Idle:      percent(delta.Idle),
+		IOWait:    percent(delta.IOWait),
+		IRQ:       percent(delta.IRQ),
+		SoftIRQ:   percent(delta.SoftIRQ),
+		Steal:     percent(delta.Steal),
+		Guest:     percent(delta.Guest),
+		GuestNice: percent(delta.GuestNice),
+		Usage:     percent(total - delta.Idle - delta.IOWait),
+	}, true
+}
+
+// CalculateCPUUsage returns the utilisation between two /proc/stat reads.
+// CPUs missing from either read, because they went offline or came online
+// in between, and CPUs whose counters were reset are left out of CPUs.
+func CalculateCPUUsage(previous, current *Stat) *CPUUsage {
+	var (
+		usage = &CPUUsage{CPUs: make([]CPUUtilisation, 0)}
+		cpus  = make(map[string]*CPUStat)
+	)
+	for i := range previous.CPUStats {
+		cpus[previous.CPUStats[i].CPUId] = &previous.CPUStats[i]
+	}
+	for i := range current.CPUStats {
+		stat := &current.CPUStats[i]
+		last, ok := cpus[stat.CPUId]
+		if !ok {
+			if len(previous.CPUStats) > 0 {
+				continue
+			}
+			last = &CPUStat{CPUId: stat.CPUId}
+		}
+		utilisation, ok := _CPUStatDelta(last, stat)
+		if stat.CPUId == StatCPU {
+			usage.Total = utilisation
+			continue
+		}
+		if ok {
+			usage.CPUs = append(usage.CPUs, utilisation)
+		}
+	}
+	return usage
+}
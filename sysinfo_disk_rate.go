@@ -0,0 +1,154 @@
+package sysinfo_go
+
+import (
+	"bytes"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DiskStatSectorSize is the unit of the sector counters in /proc/diskstats.
+// The kernel always counts 512 byte sectors there, whatever the logical
+// sector size of the device, see Documentation/block/stat.rst.
+const DiskStatSectorSize = 512
+
+const (
+	BlockClassDirectory  = "/sys/class/block"
+	LogicalBlockSizeFile = "queue/logical_block_size"
+)
+
+func GetLogicalSectorSize(device string) (int64, error) {
+	return DefaultCollector.GetLogicalSectorSize(device)
+}
+
+// GetLogicalSectorSize reads the logical sector size of a whole disk from
+// sysfs. Partitions have no queue directory of their own and fail.
+func (c *Collector) GetLogicalSectorSize(device string) (int64, error) {
+	contents, err := c._ReadSysFile(BlockClassDirectory, device, LogicalBlockSizeFile)
+	if nil != err {
+		return 0, err
+	}
+	return strconv.ParseInt(FastBytesToString(bytes.TrimSpace(contents)), 10, 64)
+}
+
+func _Await(ticks, ios uint64) float64 {
+	if ios == 0 {
+		return 0
+	}
+	return float64(ticks) / float64(ios)
+}
+
+// CalculateDiskRates returns iostat style metrics for devices present in
+// both snapshots. Await values are in milliseconds per request and
+// Utilisation is the percentage of the interval the device was busy.
+// Throughput always counts 512 byte sectors, the unit of /proc/diskstats,
+// rather than the logical sector size of the device, which is only reported.
+func CalculateDiskRates(previous, current DiskStats, interval time.Duration) DiskRates {
+	rates := make(DiskRates, 0, len(current))
+	if interval <= 0 {
+		return rates
+	}
+	var (
+		seconds      = interval.Seconds()
+		milliseconds = seconds * 1000
+		devices      = make(map[string]*DiskStat)
+	)
+	for i := range previous {
+		devices[previous[i].Device] = &previous[i]
+	}
+	for i := range current {
+		stat := &current[i]
+		last, ok := devices[stat.Device]
+		if !ok {
+			continue
+		}
+		var (
+			reads        = _CounterIncrease(last.ReadsComplete, stat.ReadsComplete)
+			writes       = _CounterIncrease(last.WritesComplete, stat.WritesComplete)
+			discards     = _CounterIncrease(last.DiscardsComplete, stat.DiscardsComplete)
+			readTicks    = _CounterIncrease(last.ReadingTime, stat.ReadingTime)
+			writeTicks   = _CounterIncrease(last.WritingTime, stat.WritingTime)
+			discardTicks = _CounterIncrease(last.DiscardingTime, stat.DiscardingTime)
+			busy         = float64(_CounterIncrease(last.TotalIOTime, stat.TotalIOTime))
+			weighted     = float64(_CounterIncrease(last.WeightedIOTime, stat.WeightedIOTime))
+			sectorBytes  = func(previous, current int64) float64 {
+				return float64(_CounterIncrease(previous, current)) * DiskStatSectorSize / seconds
+			}
+		)
+		utilisation := busy / milliseconds * 100
+		if utilisation > 100 {
+			utilisation = 100
+		}
+		rates = append(rates, DiskRate{
+			Major:             stat.Major,
+			Minor:             stat.Minor,
+			Device:            stat.Device,
+			ReadsPerSecond:    float64(reads) / seconds,
+			WritesPerSecond:   float64(writes) / seconds,
+			DiscardsPerSecond: float64(discards) / seconds,
+			ReadMerges:        _CounterRate(last.ReadsMerged, stat.ReadsMerged, seconds),
+			WriteMerges:       _CounterRate(last.WritesMerged, stat.WritesMerged, seconds),
+			DiscardMerges:     _CounterRate(last.DiscardsMerged, stat.DiscardsMerged, seconds),
+			ReadBytes:         sectorBytes(last.SectorsRead, stat.SectorsRead),
+			WriteBytes:        sectorBytes(last.SectorsWritten, stat.SectorsWritten),
+			DiscardBytes:      sectorBytes(last.SectorsDiscarded, stat.SectorsDiscarded),
+			ReadAwait:         _Await(readTicks, reads),
+			WriteAwait:        _Await(writeTicks, writes),
+			DiscardAwait:      _Await(discardTicks, discards),
+			Await:             _Await(readTicks+writeTicks+discardTicks, reads+writes+discards),
+			AverageQueueSize:  weighted / milliseconds,
+			Utilisation:       utilisation,
+			InProgress:        stat.IOInProgess,
+			ServiceTime:       _Await(uint64(busy), reads+writes+discards),
+		})
+	}
+	return rates
+}
+
+// DiskRateSampler reports disk activity between successive DiskStats
+// snapshots.
+type DiskRateSampler struct {
+	collector *Collector
+	mutex     sync.Mutex
+	previous  DiskStats
+	time      time.Time
+}
+
+func NewDiskRateSampler(collector *Collector) *DiskRateSampler {
+	if nil == collector {
+		collector = DefaultCollector
+	}
+	return &DiskRateSampler{
+		collector: collector,
+	}
+}
+
+// Sample reads /proc/diskstats and returns the rates since the previous
+// snapshot, with the logical sector size of each whole disk filled in from
+// sysfs. The first call only records the snapshot and returns no rates.
+func (s *DiskRateSampler) Sample() (DiskRates, error) {
+	stats, err := s.collector.GetDiskStats()
+	if nil != err {
+		return nil, err
+	}
+	rates := s.Update(stats, time.Now())
+	for i := range rates {
+		if size, err := s.collector.GetLogicalSectorSize(rates[i].Device); nil == err {
+			rates[i].LogicalSectorSize = size
+		}
+	}
+	return rates, nil
+}
+
+// Update records stats taken at the given time and returns the rates since
+// the previous snapshot.
+func (s *DiskRateSampler) Update(stats DiskStats, at time.Time) DiskRates {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	rates := make(DiskRates, 0)
+	if !s.time.IsZero() {
+		rates = CalculateDiskRates(s.previous, stats, at.Sub(s.time))
+	}
+	s.previous, s.time = stats, at
+	return rates
+}
@@ -0,0 +1,323 @@
+package sysinfo_go
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	OpenMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+	InfluxContentType      = "text/plain; charset=utf-8"
+)
+
+const (
+	MeasurementCPU        = "cpu"
+	MeasurementKernel     = "kernel"
+	MeasurementMemory     = "mem"
+	MeasurementDisk       = "diskio"
+	MeasurementNetwork    = "net"
+	MeasurementFileSystem = "filesystem"
+	MeasurementLoad       = "load"
+)
+
+const (
+	TagHost = "host"
+)
+
+// EncoderOptions configure EncodeOpenMetrics and EncodeInflux.
+type EncoderOptions struct {
+	// Namespace prefixes OpenMetrics family names, PrometheusNamespace when
+	// empty.
+	Namespace string
+	// Measurements renames the Influx measurements, keyed by the
+	// Measurement* defaults.
+	Measurements map[string]string
+	// Tags are added to every Influx point and as labels to every
+	// OpenMetrics sample.
+	Tags map[string]string
+	// Timestamp stamps every point and sample, the time of encoding when
+	// zero.
+	Timestamp time.Time
+}
+
+// NewEncoderOptions returns options tagging everything with the host name
+// reported by GetUName.
+func NewEncoderOptions() (*EncoderOptions, error) {
+	uname, err := GetUName()
+	if nil != err {
+		return nil, err
+	}
+	return &EncoderOptions{
+		Measurements: make(map[string]string),
+		Tags:         map[string]string{TagHost: uname.NodeName},
+	}, nil
+}
+
+func (o *EncoderOptions) _Timestamp() time.Time {
+	if nil == o || o.Timestamp.IsZero() {
+		return time.Now()
+	}
+	return o.Timestamp
+}
+
+func (o *EncoderOptions) _Measurement(name string) string {
+	if nil != o {
+		if v, ok := o.Measurements[name]; ok && len(v) > 0 {
+			return v
+		}
+	}
+	return name
+}
+
+// _Tags returns the option tags sorted by key, as line protocol recommends.
+func (o *EncoderOptions) _Tags() []MetricLabel {
+	tags := make([]MetricLabel, 0)
+	if nil == o {
+		return tags
+	}
+	for k, v := range o.Tags {
+		tags = append(tags, MetricLabel{Name: k, Value: v})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].Name < tags[j].Name
+	})
+	return tags
+}
+
+// _MetricFamiliesOf converts a snapshot of one of the collector structs into
+// metric families.
+func _MetricFamiliesOf(value interface{}) (MetricFamilies, error) {
+	switch v := value.(type) {
+	case *Stat:
+		return _StatMetrics(v), nil
+	case []CPUStat:
+		return _CPUStatsMetrics(v), nil
+	case CPUStat:
+		return _CPUStatsMetrics([]CPUStat{v}), nil
+	case *MemInfo:
+		return _MemInfoMetrics(v), nil
+	case *VMStat:
+		return _VMStatMetrics(v), nil
+	case *Load:
+		return _LoadMetrics(v), nil
+	case DiskStats:
+		return _DiskStatsMetrics(v), nil
+	case DiskStat:
+		return _DiskStatsMetrics(DiskStats{v}), nil
+	case NetworkStats:
+		return _NetworkStatsMetrics(v), nil
+	case NetworkStat:
+		return _NetworkStatsMetrics(NetworkStats{v}), nil
+	case FileSystemUsages:
+		return _FileSystemUsageMetrics(v), nil
+	case *FileSystemStat:
+		return _FileSystemUsageMetrics(FileSystemUsages{{Stat: *v}}), nil
+	case *PressureStats:
+		return _PressureMetrics(v), nil
+	default:
+		return nil, fmt.Errorf("cannot encode %T", value)
+	}
+}
+
+// _FormatTimestamp prints a timestamp in seconds with nanosecond precision.
+func _FormatTimestamp(at time.Time) string {
+	ns := at.UnixNano()
+	return strconv.FormatInt(ns/1e9, 10) + "." + fmt.Sprintf("%09d", ns%1e9)
+}
+
+func _WriteOpenMetrics(w io.Writer, families MetricFamilies, timestamp string) error {
+	writer := bufio.NewWriter(w)
+	for _, family := range families {
+		if len(family.Metrics) == 0 {
+			continue
+		}
+		// OpenMetrics names counter families without the _total suffix
+		// their samples carry.
+		name := family.Name
+		if family.Type == MetricCounter {
+			name = strings.TrimSuffix(name, "_total")
+		}
+		_, _ = writer.WriteString("# TYPE " + name + " " + family.Type + "\n")
+		for _, unit := range []string{"bytes", "seconds"} {
+			if strings.HasSuffix(name, "_"+unit) {
+				_, _ = writer.WriteString("# UNIT " + name + " " + unit + "\n")
+			}
+		}
+		_, _ = writer.WriteString("# HELP " + name + " " + _LabelEscaper.Replace(family.Help) + "\n")
+		for _, metric := range family.Metrics {
+			_WriteMetric(writer, family.Name, metric, timestamp)
+		}
+	}
+	_, _ = writer.WriteString("# EOF\n")
+	return writer.Flush()
+}
+
+// WriteOpenMetrics renders families in the OpenMetrics text format.
+func WriteOpenMetrics(w io.Writer, families MetricFamilies) error {
+	return _WriteOpenMetrics(w, families, "")
+}
+
+// EncodeOpenMetrics renders snapshots of the collector structs, such as
+// *Stat, *MemInfo, DiskStats, NetworkStats, *FileSystemStat or *Load, as
+// one OpenMetrics exposition.
+func EncodeOpenMetrics(w io.Writer, options *EncoderOptions, values ...interface{}) error {
+	var (
+		families = make(MetricFamilies, 0)
+		tags     = options._Tags()
+	)
+	namespace := PrometheusNamespace
+	if nil != options && len(options.Namespace) > 0 {
+		namespace = options.Namespace
+	}
+	for _, value := range values {
+		converted, err := _MetricFamiliesOf(value)
+		if nil != err {
+			return err
+		}
+		families = append(families, converted...)
+	}
+	for i := range families {
+		families[i].Name = namespace + "_" + families[i].Name
+		if len(tags) == 0 {
+			continue
+		}
+		for j := range families[i].Metrics {
+			metric := &families[i].Metrics[j]
+			metric.Labels = append(append(make([]MetricLabel, 0, len(metric.Labels)+len(tags)), metric.Labels...), tags...)
+		}
+	}
+	return _WriteOpenMetrics(w, families, _FormatTimestamp(options._Timestamp()))
+}
+
+var (
+	_MeasurementEscaper = strings.NewReplacer(",", `\,`, " ", `\ `, "\n", `\n`)
+	_TagEscaper         = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `, "\n", `\n`)
+)
+
+// _InfluxPoint flattens a struct into line protocol: string fields become
+// tags and numeric and boolean fields become fields, both named after their
+// JSON names. Slices, maps, nested structs and empty strings are skipped.
+func _InfluxPoint(value interface{}) ([]MetricLabel, []string) {
+	var (
+		v      = reflect.Indirect(reflect.ValueOf(value))
+		t      = v.Type()
+		tags   = make([]MetricLabel, 0)
+		fields = make([]string, 0, t.NumField())
+	)
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if len(name) == 0 || name == "-" {
+			name = t.Field(i).Name
+		}
+		key := _TagEscaper.Replace(name)
+		field := v.Field(i)
+		switch field.Kind() {
+		case reflect.String:
+			if len(field.String()) > 0 {
+				tags = append(tags, MetricLabel{Name: name, Value: field.String()})
+			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			fields = append(fields, key+"="+strconv.FormatInt(field.Int(), 10)+"i")
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			fields = append(fields, key+"="+strconv.FormatUint(field.Uint(), 10)+"u")
+		case reflect.Float32, reflect.Float64:
+			// Line protocol has no representation for NaN and infinities.
+			if !math.IsNaN(field.Float()) && !math.IsInf(field.Float(), 0) {
+				fields = append(fields, key+"="+strconv.FormatFloat(field.Float(), 'g', -1, 64))
+			}
+		case reflect.Bool:
+			fields = append(fields, key+"="+strconv.FormatBool(field.Bool()))
+		default:
+			// Do Nothing
+		}
+	}
+	return tags, fields
+}
+
+type _InfluxWriter struct {
+	writer    *bufio.Writer
+	options   *EncoderOptions
+	tags      []MetricLabel
+	timestamp string
+}
+
+func (w *_InfluxWriter) _Write(measurement string, value interface{}, tags ...MetricLabel) {
+	own, fields := _InfluxPoint(value)
+	if len(fields) == 0 {
+		return
+	}
+	tags = append(append(append(make([]MetricLabel, 0), w.tags...), tags...), own...)
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].Name < tags[j].Name
+	})
+	_, _ = w.writer.WriteString(_MeasurementEscaper.Replace(w.options._Measurement(measurement)))
+	for _, tag := range tags {
+		if len(tag.Value) == 0 {
+			continue
+		}
+		_, _ = w.writer.WriteString("," + _TagEscaper.Replace(tag.Name) + "=" + _TagEscaper.Replace(tag.Value))
+	}
+	_, _ = w.writer.WriteString(" " + strings.Join(fields, ",") + " " + w.timestamp + "\n")
+}
+
+// EncodeInflux writes snapshots of the collector structs as InfluxDB line
+// protocol points with nanosecond timestamps, one point per CPU, disk,
+// interface or filesystem. Measurements default to the Measurement* names.
+func EncodeInflux(w io.Writer, options *EncoderOptions, values ...interface{}) error {
+	writer := &_InfluxWriter{
+		writer:    bufio.NewWriter(w),
+		options:   options,
+		tags:      options._Tags(),
+		timestamp: strconv.FormatInt(options._Timestamp().UnixNano(), 10),
+	}
+	for _, value := range values {
+		switch v := value.(type) {
+		case *Stat:
+			for _, cpu := range v.CPUStats {
+				writer._Write(MeasurementCPU, cpu)
+			}
+			writer._Write(MeasurementKernel, v)
+		case []CPUStat:
+			for _, cpu := range v {
+				writer._Write(MeasurementCPU, cpu)
+			}
+		case CPUStat:
+			writer._Write(MeasurementCPU, v)
+		case *MemInfo:
+			writer._Write(MeasurementMemory, v)
+		case *Load:
+			writer._Write(MeasurementLoad, v)
+		case DiskStats:
+			for _, disk := range v {
+				writer._Write(MeasurementDisk, disk)
+			}
+		case DiskStat:
+			writer._Write(MeasurementDisk, v)
+		case NetworkStats:
+			for _, network := range v {
+				writer._Write(MeasurementNetwork, network)
+			}
+		case NetworkStat:
+			writer._Write(MeasurementNetwork, v)
+		case FileSystemUsages:
+			for _, usage := range v {
+				writer._Write(MeasurementFileSystem, usage.Stat,
+					MetricLabel{Name: "device", Value: usage.Mount.Source},
+					MetricLabel{Name: "mountPoint", Value: usage.Mount.MountPoint},
+				)
+			}
+		case *FileSystemStat:
+			writer._Write(MeasurementFileSystem, v)
+		default:
+			return fmt.Errorf("cannot encode %T", value)
+		}
+	}
+	return writer.writer.Flush()
+}
@@ -0,0 +1,215 @@
+package sysinfo_go
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"regexp"
+	"strings"
+)
+
+const (
+	ContainerDocker     = "docker"
+	ContainerContainerd = "containerd"
+	ContainerCRIO       = "cri-o"
+	ContainerPodman     = "podman"
+	ContainerLXC        = "lxc"
+	ContainerNspawn     = "systemd-nspawn"
+)
+
+const (
+	DockerEnvFile         = "/.dockerenv"
+	PodmanEnvFile         = "/run/.containerenv"
+	SystemdContainerFile  = "/run/systemd/container"
+	KubernetesSecretsPath = "/var/run/secrets/kubernetes.io"
+	OSReleaseFile         = "/proc/sys/kernel/osrelease"
+	HypervisorTypeFile    = "/sys/hypervisor/type"
+	DMISystemVendorFile   = "/sys/class/dmi/id/sys_vendor"
+	DMIProductNameFile    = "/sys/class/dmi/id/product_name"
+	ProcessEnvironFile    = "environ"
+)
+
+const (
+	CPUInfoHypervisorFlag = "hypervisor"
+	KubernetesServiceHost = "KUBERNETES_SERVICE_HOST"
+)
+
+var (
+	_ContainerIdPattern      = regexp.MustCompile(`[0-9a-f]{64}`)
+	_MountContainerIdPattern = regexp.MustCompile(`containers/([0-9a-f]{64})/`)
+	_PodUidPattern           = regexp.MustCompile(`pod([0-9a-f]{8}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{12})`)
+	_KubeletPodUidPattern    = regexp.MustCompile(`/var/lib/kubelet/pods/([0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12})/`)
+)
+
+// _CgroupRuntimes maps markers found in cgroup paths and container storage
+// paths onto the runtime that created them, most specific first.
+var _CgroupRuntimes = []struct {
+	marker  string
+	runtime string
+}{
+	{"cri-containerd-", ContainerContainerd},
+	{"crio-", ContainerCRIO},
+	{"libpod-", ContainerPodman},
+	{"docker-", ContainerDocker},
+	{"/docker/", ContainerDocker},
+	{"/var/lib/docker/", ContainerDocker},
+	{"/var/lib/containerd/", ContainerContainerd},
+	{"/lxc/", ContainerLXC},
+	{"lxc.payload", ContainerLXC},
+}
+
+// _DMIHypervisors maps DMI vendor and product names onto hypervisors.
+var _DMIHypervisors = []struct {
+	marker     string
+	hypervisor string
+}{
+	{"QEMU", "kvm"},
+	{"KVM", "kvm"},
+	{"Amazon EC2", "kvm"},
+	{"Google Compute Engine", "kvm"},
+	{"VMware", "vmware"},
+	{"VirtualBox", "virtualbox"},
+	{"innotek", "virtualbox"},
+	{"Xen", "xen"},
+	{"Parallels", "parallels"},
+	{"Virtual Machine", "hyperv"},
+	{"bhyve", "bhyve"},
+}
+
+func _DetectPath(value string, env *Environment) {
+	if strings.Contains(value, "kubepods") || strings.Contains(value, "/var/lib/kubelet/") {
+		env.Kubernetes = true
+	}
+	if match := _PodUidPattern.FindStringSubmatch(value); len(env.PodUid) == 0 && nil != match {
+		env.PodUid = strings.ReplaceAll(match[1], "_", "-")
+	}
+	if match := _KubeletPodUidPattern.FindStringSubmatch(value); len(env.PodUid) == 0 && nil != match {
+		env.PodUid = match[1]
+	}
+	if len(env.Runtime) == 0 {
+		for _, it := range _CgroupRuntimes {
+			if strings.Contains(value, it.marker) {
+				env.Runtime = it.runtime
+				break
+			}
+		}
+	}
+}
+
+// _DetectContainer looks at the cgroup paths of the process first and, since
+// cgroup namespaces hide those, at the roots of its mounts, such as the
+// hostname and resolv.conf files runtimes bind mount from their storage.
+func _DetectContainer(cgroups ProcessCgroups, mounts Mounts, env *Environment) {
+	for _, cgroup := range cgroups {
+		_DetectPath(cgroup.Path, env)
+		// Nested groups such as kubepods/pod<uid>/<id> end with the container.
+		if ids := _ContainerIdPattern.FindAllString(cgroup.Path, -1); len(env.ContainerId) == 0 && len(ids) > 0 {
+			env.ContainerId = ids[len(ids)-1]
+		}
+	}
+	for _, mount := range mounts {
+		_DetectPath(mount.Root, env)
+		if match := _MountContainerIdPattern.FindStringSubmatch(mount.Root); len(env.ContainerId) == 0 && nil != match {
+			env.ContainerId = match[1]
+		}
+	}
+	env.Container = len(env.Runtime) > 0 || len(env.ContainerId) > 0 || env.Kubernetes
+}
+
+func (c *Collector) _Exists(name string) bool {
+	_, err := fs.Stat(c._FS(), _Resolve(name, "/"))
+	return nil == err
+}
+
+// _ReadTrimmed reads a single value file, treating unreadable files as
+// empty.
+func (c *Collector) _ReadTrimmed(name string) string {
+	contents, err := fs.ReadFile(c._FS(), name)
+	if nil != err {
+		return ""
+	}
+	return string(bytes.TrimSpace(contents))
+}
+
+// _HasEnvironmentVariable looks up a variable in the NUL separated environ
+// file of a process.
+func _HasEnvironmentVariable(data []byte, name string) bool {
+	for _, item := range bytes.Split(data, []byte{0}) {
+		if bytes.HasPrefix(item, []byte(name+"=")) {
+			return true
+		}
+	}
+	return false
+}
+
+func DetectEnvironment() (*Environment, error) {
+	return DefaultCollector.DetectEnvironment()
+}
+
+// DetectEnvironment reports the container runtime, Kubernetes pod, WSL and
+// virtual machine the calling process runs in, as far as they can be told
+// from procfs, sysfs and marker files. Sources that cannot be read are
+// skipped.
+func (c *Collector) DetectEnvironment() (*Environment, error) {
+	env := new(Environment)
+	switch {
+	case c._Exists(DockerEnvFile):
+		env.Runtime = ContainerDocker
+	case c._Exists(PodmanEnvFile):
+		env.Runtime = ContainerPodman
+	default:
+		// systemd and the runtimes that follow its container interface
+		// name themselves in /run/systemd/container.
+		switch value := c._ReadTrimmed(_Resolve(SystemdContainerFile, "/")); value {
+		case "lxc", "lxc-libvirt":
+			env.Runtime = ContainerLXC
+		default:
+			env.Runtime = value
+		}
+	}
+	if c._Exists(KubernetesSecretsPath) {
+		env.Kubernetes = true
+	}
+	if contents, err := c._ReadProcFile("self", ProcessEnvironFile); nil == err {
+		env.Kubernetes = env.Kubernetes || _HasEnvironmentVariable(contents, KubernetesServiceHost)
+	}
+	cgroups, err := c._GetProcessCgroups("self")
+	if nil != err && !errors.Is(err, ErrProcessNotFound) {
+		return nil, err
+	}
+	mounts, err := c.GetMounts()
+	if nil != err && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	_DetectContainer(cgroups, mounts, env)
+	// CRI-O creates /run/.containerenv for its containers too.
+	if env.Kubernetes && env.Runtime == ContainerPodman {
+		env.Runtime = ContainerCRIO
+	}
+
+	release := c._ReadTrimmed(c._ProcPath(OSReleaseFile))
+	if strings.Contains(strings.ToLower(release), "microsoft") {
+		env.WSL = true
+	}
+
+	env.SystemVendor = c._ReadTrimmed(c._SysPath(DMISystemVendorFile))
+	env.ProductName = c._ReadTrimmed(c._SysPath(DMIProductNameFile))
+	for _, it := range _DMIHypervisors {
+		if strings.Contains(env.SystemVendor, it.marker) || strings.Contains(env.ProductName, it.marker) {
+			env.Hypervisor = it.hypervisor
+			break
+		}
+	}
+	if len(env.Hypervisor) == 0 {
+		env.Hypervisor = c._ReadTrimmed(c._SysPath(HypervisorTypeFile))
+	}
+	env.Virtualized = len(env.Hypervisor) > 0 || env.WSL
+	if info, err := c.GetCPUInfo(); nil == err && len(info.Processors) > 0 {
+		for _, flag := range info.Processors[0].Flags {
+			if flag == CPUInfoHypervisorFlag {
+				env.Virtualized = true
+			}
+		}
+	}
+	return env, nil
+}
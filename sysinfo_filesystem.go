@@ -0,0 +1,95 @@
+package sysinfo_go
+
+// Mount flags reported by statfs(2) in Statfs_t.Flags.
+const (
+	MountFlagReadOnly    = 0x0001
+	MountFlagNoSUID      = 0x0002
+	MountFlagNoDev       = 0x0004
+	MountFlagNoExec      = 0x0008
+	MountFlagSynchronous = 0x0010
+	MountFlagValid       = 0x0020
+	MountFlagMandLock    = 0x0040
+	MountFlagNoAtime     = 0x0400
+	MountFlagNoDirAtime  = 0x0800
+	MountFlagRelAtime    = 0x1000
+)
+
+var _MountFlagNames = []struct {
+	flag int64
+	name string
+}{
+	{MountFlagReadOnly, "ro"},
+	{MountFlagNoSUID, "nosuid"},
+	{MountFlagNoDev, "nodev"},
+	{MountFlagNoExec, "noexec"},
+	{MountFlagSynchronous, "sync"},
+	{MountFlagMandLock, "mand"},
+	{MountFlagNoAtime, "noatime"},
+	{MountFlagNoDirAtime, "nodiratime"},
+	{MountFlagRelAtime, "relatime"},
+}
+
+// FileSystemMagics maps the Statfs_t.Type magic numbers from
+// include/uapi/linux/magic.h onto filesystem names. ext2, ext3 and ext4
+// share a magic number and are all reported as ext4.
+var FileSystemMagics = map[int64]string{
+	0x0000EF53: "ext4",
+	0x58465342: "xfs",
+	0x9123683E: "btrfs",
+	0xCA451A4E: "bcachefs",
+	0x2FC12FC1: "zfs",
+	0xF2F52010: "f2fs",
+	0x3153464A: "jfs",
+	0x52654973: "reiserfs",
+	0x01021994: "tmpfs",
+	0x858458F6: "ramfs",
+	0x794C7630: "overlay",
+	0x73717368: "squashfs",
+	0xE0F5E1E2: "erofs",
+	0x28CD3D45: "cramfs",
+	0x24051905: "ubifs",
+	0x00009660: "iso9660",
+	0x15013346: "udf",
+	0x00004D44: "vfat",
+	0x5346544E: "ntfs",
+	0x2011BAB0: "exfat",
+	0x00006969: "nfs",
+	0xFF534D42: "cifs",
+	0xFE534D42: "smb2",
+	0x01021997: "9p",
+	0x65735546: "fuse",
+	0x0BD00BD0: "lustre",
+	0x47504653: "gpfs",
+	0x00009FA0: "proc",
+	0x62656572: "sysfs",
+	0x00001CD1: "devpts",
+	0x0027E0EB: "cgroup",
+	0x63677270: "cgroup2",
+	0x64626720: "debugfs",
+	0x74726163: "tracefs",
+	0x73636673: "securityfs",
+	0xCAFE4A11: "bpf",
+	0x958458F6: "hugetlbfs",
+	0x6E736673: "nsfs",
+	0x19800202: "mqueue",
+	0x6165676C: "pstore",
+	0xDE5E81E4: "efivarfs",
+	0x42494E4D: "binfmt_misc",
+}
+
+func _MountFlags(flags int64) []string {
+	names := make([]string, 0)
+	for _, it := range _MountFlagNames {
+		if flags&it.flag != 0 {
+			names = append(names, it.name)
+		}
+	}
+	return names
+}
+
+func _Percentage(used, total int64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(used) / float64(total) * 100
+}
@@ -0,0 +1,158 @@
+package sysinfo_go
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func _IsInterruptTrigger(value string) bool {
+	switch strings.ToLower(value) {
+	case "edge", "level":
+		return true
+	default:
+		return false
+	}
+}
+
+func _ParseInterrupt(irq string, fields [][]byte, cpus int) Interrupt {
+	interrupt := Interrupt{
+		IRQ:     irq,
+		Counts:  make([]int64, 0, cpus),
+		Devices: make([]string, 0),
+	}
+	i := 0
+	for ; i < len(fields) && i < cpus; i++ {
+		v, err := strconv.ParseInt(FastBytesToString(fields[i]), 10, 64)
+		if nil != err {
+			break
+		}
+		interrupt.Counts = append(interrupt.Counts, v)
+		interrupt.Total = interrupt.Total + v
+	}
+	rest := make([]string, 0, len(fields)-i)
+	for _, field := range fields[i:] {
+		rest = append(rest, string(field))
+	}
+	if _, err := strconv.Atoi(irq); nil != err {
+		// Architecture specific rows such as NMI or LOC only carry a
+		// description.
+		interrupt.Description = strings.Join(rest, " ")
+		return interrupt
+	}
+	if len(rest) == 0 {
+		return interrupt
+	}
+	interrupt.Chip, rest = rest[0], rest[1:]
+	// The hardware irq is printed either as "5-edge" or as "30 Level".
+	if len(rest) > 0 && len(rest[0]) > 0 && rest[0][0] >= '0' && rest[0][0] <= '9' {
+		items := strings.SplitN(rest[0], "-", 2)
+		interrupt.HardwareIRQ = items[0]
+		if len(items) == 2 {
+			interrupt.Trigger = items[1]
+		}
+		rest = rest[1:]
+	}
+	if len(rest) > 0 && len(interrupt.Trigger) == 0 && _IsInterruptTrigger(rest[0]) {
+		interrupt.Trigger, rest = rest[0], rest[1:]
+	}
+	if len(rest) > 0 {
+		for _, device := range strings.Split(strings.Join(rest, " "), ",") {
+			if device = strings.TrimSpace(device); len(device) > 0 {
+				interrupt.Devices = append(interrupt.Devices, device)
+			}
+		}
+	}
+	return interrupt
+}
+
+func _ParseInterrupts(data []byte) (*InterruptStats, error) {
+	var (
+		newline = []byte("\n")
+		stats   = &InterruptStats{
+			CPUs:       make([]string, 0),
+			Interrupts: make([]Interrupt, 0),
+		}
+	)
+	lines := bytes.Split(data, newline)
+	if len(lines) == 0 {
+		return nil, errors.New("incorrectly formatted interrupts content")
+	}
+	for _, cpu := range bytes.Fields(lines[0]) {
+		stats.CPUs = append(stats.CPUs, string(cpu))
+	}
+	for _, line := range lines[1:] {
+		fields := bytes.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if !bytes.HasSuffix(fields[0], []byte(":")) {
+			return nil, errors.New("incorrectly formatted interrupts content")
+		}
+		irq := string(bytes.TrimSuffix(fields[0], []byte(":")))
+		stats.Interrupts = append(stats.Interrupts, _ParseInterrupt(irq, fields[1:], len(stats.CPUs)))
+	}
+	return stats, nil
+}
+
+func GetInterrupts() (*InterruptStats, error) {
+	return DefaultCollector.GetInterrupts()
+}
+
+func (c *Collector) GetInterrupts() (*InterruptStats, error) {
+	contents, err := c._ReadProcFile(InterruptFile)
+	if nil != err {
+		return nil, err
+	}
+	return _ParseInterrupts(contents)
+}
+
+// CalculateInterruptRates returns interrupts per second for every irq present
+// in both reads. Per CPU rates follow current.CPUs; a CPU missing from the
+// previous read, or a counter that went backwards, reports zero.
+func CalculateInterruptRates(previous, current *InterruptStats, interval time.Duration) []InterruptRate {
+	rates := make([]InterruptRate, 0, len(current.Interrupts))
+	if interval <= 0 {
+		return rates
+	}
+	var (
+		seconds    = interval.Seconds()
+		columns    = make(map[string]int)
+		interrupts = make(map[string]*Interrupt)
+	)
+	for i, cpu := range previous.CPUs {
+		columns[cpu] = i
+	}
+	for i := range previous.Interrupts {
+		interrupts[previous.Interrupts[i].IRQ] = &previous.Interrupts[i]
+	}
+	for i := range current.Interrupts {
+		interrupt := &current.Interrupts[i]
+		last, ok := interrupts[interrupt.IRQ]
+		if !ok {
+			continue
+		}
+		rate := InterruptRate{
+			IRQ:     interrupt.IRQ,
+			Devices: interrupt.Devices,
+			Rates:   make([]float64, len(interrupt.Counts)),
+		}
+		for j, count := range interrupt.Counts {
+			column := j
+			if j < len(current.CPUs) {
+				if column, ok = columns[current.CPUs[j]]; !ok {
+					continue
+				}
+			}
+			if column >= len(last.Counts) || count < last.Counts[column] {
+				continue
+			}
+			rate.Rates[j] = float64(count-last.Counts[column]) / seconds
+			rate.Total = rate.Total + rate.Rates[j]
+		}
+		rates = append(rates, rate)
+	}
+	return rates
+}
@@ -0,0 +1,278 @@
+package sysinfo_go
+
+import (
+	"errors"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	CPUOnlineFile = "/sys/devices/system/cpu/online"
+)
+
+const (
+	CgroupCPUSetEffectiveFile   = "cpuset.cpus.effective"
+	CgroupV1CPUSetEffectiveFile = "cpuset.effective_cpus"
+	CgroupV1MemoryHierarchical  = "hierarchical_memory_limit"
+)
+
+// _ParseCPUList counts the CPUs in a list such as "0-3,8,10-11".
+func _ParseCPUList(data []byte) (int64, error) {
+	var (
+		value = strings.TrimSpace(string(data))
+		count = int64(0)
+	)
+	if len(value) == 0 {
+		return 0, nil
+	}
+	for _, item := range strings.Split(value, ",") {
+		bounds := strings.SplitN(item, "-", 2)
+		first, err := strconv.ParseInt(bounds[0], 10, 64)
+		if nil != err {
+			return 0, err
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.ParseInt(bounds[1], 10, 64); nil != err {
+				return 0, err
+			}
+		}
+		if last < first {
+			return 0, errors.New("incorrectly formatted cpu list content")
+		}
+		count = count + last - first + 1
+	}
+	return count, nil
+}
+
+func (c *Collector) _HostCPUs() (int64, error) {
+	if contents, err := c._ReadSysFile(CPUOnlineFile); nil == err {
+		return _ParseCPUList(contents)
+	}
+	stat, err := c.GetStat()
+	if nil != err {
+		return 0, err
+	}
+	count := int64(0)
+	for _, cpu := range stat.CPUStats {
+		if cpu.CPUId != StatCPU {
+			count = count + 1
+		}
+	}
+	return count, nil
+}
+
+// _CgroupV2Limits walks from the group up to the root of the mount, since a
+// group is bound by the limits of all its ancestors.
+func (c *Collector) _CgroupV2Limits(group string, limits *EffectiveLimits) error {
+	root, group, ok := c._CgroupV2Group(group)
+	if !ok {
+		return nil
+	}
+	if contents, ok, err := c._ReadCgroupFile(path.Join(root, group), CgroupMemoryCurrentFile); nil != err {
+		return err
+	} else if ok {
+		if limits.MemoryUsage, err = _ParseCgroupValue(contents); nil != err {
+			return err
+		}
+	}
+	if contents, ok, err := c._ReadCgroupFile(path.Join(root, group), CgroupCPUSetEffectiveFile); nil != err {
+		return err
+	} else if ok {
+		if limits.CPUSetCPUs, err = _ParseCPUList(contents); nil != err {
+			return err
+		}
+	}
+	for ; ; group = path.Dir(group) {
+		directory := path.Join(root, group)
+		if contents, ok, err := c._ReadCgroupFile(directory, CgroupCPUMaxFile); nil != err {
+			return err
+		} else if ok {
+			limit, err := _ParseCgroupCPUMax(contents)
+			if nil != err {
+				return err
+			}
+			if limit.Quota > 0 && limit.Period > 0 {
+				quota := float64(limit.Quota) / float64(limit.Period)
+				if limits.CPUQuota == 0 || quota < limits.CPUQuota {
+					limits.CPUQuota = quota
+				}
+			}
+		}
+		if contents, ok, err := c._ReadCgroupFile(directory, CgroupMemoryMaxFile); nil != err {
+			return err
+		} else if ok {
+			limit, err := _ParseCgroupValue(contents)
+			if nil != err {
+				return err
+			}
+			if limit != CgroupUnlimited && (limits.MemoryLimit == CgroupUnlimited || limit < limits.MemoryLimit) {
+				limits.MemoryLimit = limit
+			}
+		}
+		if group == "/" {
+			return nil
+		}
+	}
+}
+
+func (c *Collector) _CgroupV1Limits(cgroups ProcessCgroups, limits *EffectiveLimits) error {
+	mounts, err := c.GetMounts()
+	if nil != err {
+		return err
+	}
+	directories := _CgroupV1Directories(mounts, cgroups)
+	cgroup, err := c._GetCgroupV1(directories)
+	if nil != err {
+		return err
+	}
+	if cgroup.CPUQuota > 0 && cgroup.CPUPeriod > 0 {
+		limits.CPUQuota = float64(cgroup.CPUQuota) / float64(cgroup.CPUPeriod)
+	}
+	limits.MemoryUsage = cgroup.MemoryUsage
+	limits.MemoryLimit = cgroup.MemoryLimit
+	// Unlike memory.limit_in_bytes the hierarchical limit includes the
+	// limits of the ancestors.
+	if v, ok := cgroup.MemoryStat[CgroupV1MemoryHierarchical]; ok && v < _CgroupV1MemoryUnlimited {
+		if limits.MemoryLimit == CgroupUnlimited || v < limits.MemoryLimit {
+			limits.MemoryLimit = v
+		}
+	}
+	if directory, ok := directories["cpuset"]; ok {
+		if contents, ok, err := c._ReadCgroupFile(directory, CgroupV1CPUSetEffectiveFile); nil != err {
+			return err
+		} else if ok {
+			if limits.CPUSetCPUs, err = _ParseCPUList(contents); nil != err {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func GetEffectiveLimits() (*EffectiveLimits, error) {
+	return DefaultCollector.GetEffectiveLimits()
+}
+
+// GetEffectiveLimits combines the host CPUs and memory with the cgroup CPU
+// quota, cpuset and memory limit of the calling process. CPUs is the number
+// of CPUs worth of time the process may use, Memory the bytes it may use.
+func (c *Collector) GetEffectiveLimits() (*EffectiveLimits, error) {
+	mode, err := c.GetCgroupMode()
+	if nil != err {
+		return nil, err
+	}
+	cpus, err := c._HostCPUs()
+	if nil != err {
+		return nil, err
+	}
+	mem, err := c.GetMemInfo()
+	if nil != err {
+		return nil, err
+	}
+	limits := &EffectiveLimits{
+		CgroupMode:  mode,
+		HostCPUs:    cpus,
+		HostMemory:  mem.Total,
+		MemoryLimit: CgroupUnlimited,
+	}
+	if mode != CgroupModeNone {
+		cgroups, err := c._GetProcessCgroups("self")
+		if nil != err {
+			return nil, err
+		}
+		switch mode {
+		case CgroupModeV2:
+			for _, cgroup := range cgroups {
+				if cgroup.HierarchyId == 0 {
+					if err := c._CgroupV2Limits(cgroup.Path, limits); nil != err {
+						return nil, err
+					}
+				}
+			}
+		default:
+			// Hybrid hierarchies keep the resource controllers on v1.
+			if err := c._CgroupV1Limits(cgroups, limits); nil != err {
+				return nil, err
+			}
+		}
+	}
+	limits.CPUs = float64(limits.HostCPUs)
+	if limits.CPUSetCPUs > 0 && float64(limits.CPUSetCPUs) < limits.CPUs {
+		limits.CPUs = float64(limits.CPUSetCPUs)
+	}
+	if limits.CPUQuota > 0 && limits.CPUQuota < limits.CPUs {
+		limits.CPUs = limits.CPUQuota
+	}
+	limits.Memory = limits.HostMemory
+	if limits.MemoryLimit != CgroupUnlimited && limits.MemoryLimit < limits.Memory {
+		limits.Memory = limits.MemoryLimit
+	}
+	return limits, nil
+}
+
+func GetContainerMemInfo() (*MemInfo, error) {
+	return DefaultCollector.GetContainerMemInfo()
+}
+
+// GetContainerMemInfo returns the host MemInfo with total, free and
+// available memory scoped to the cgroup memory limit of the calling process.
+// Without a limit the host values are returned unchanged.
+func (c *Collector) GetContainerMemInfo() (*MemInfo, error) {
+	limits, err := c.GetEffectiveLimits()
+	if nil != err {
+		return nil, err
+	}
+	mem, err := c.GetMemInfo()
+	if nil != err {
+		return nil, err
+	}
+	if limits.Memory >= limits.HostMemory {
+		return mem, nil
+	}
+	free := limits.Memory - limits.MemoryUsage
+	if free < 0 {
+		free = 0
+	}
+	mem.Total = limits.Memory
+	if free < mem.Free {
+		mem.Free = free
+	}
+	if free < mem.Available {
+		mem.Available = free
+	}
+	return mem, nil
+}
+
+func GetContainerSystemInformation() (*SystemInformation, error) {
+	return DefaultCollector.GetContainerSystemInformation()
+}
+
+// GetContainerSystemInformation returns GetSystemInformation with total,
+// available and free RAM scoped to the cgroup memory limit of the calling process.
+func (c *Collector) GetContainerSystemInformation() (*SystemInformation, error) {
+	limits, err := c.GetEffectiveLimits()
+	if nil != err {
+		return nil, err
+	}
+	info, err := c.GetSystemInformation()
+	if nil != err {
+		return nil, err
+	}
+	if limits.Memory >= limits.HostMemory {
+		return info, nil
+	}
+	free := limits.Memory - limits.MemoryUsage
+	if free < 0 {
+		free = 0
+	}
+	info.TotalRam = uint64(limits.Memory)
+	if uint64(free) < info.AvailableRam {
+		info.AvailableRam = uint64(free)
+	}
+	if uint64(free) < info.FreeRam {
+		info.FreeRam = uint64(free)
+	}
+	return info, nil
+}
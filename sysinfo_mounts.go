@@ -0,0 +1,226 @@
+package sysinfo_go
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+const (
+	MountInfoFile = "/proc/self/mountinfo"
+)
+
+// ErrStatFSUnsupported is returned by GetFileSystemUsage for collectors
+// whose mounts cannot be queried with statfs.
+var ErrStatFSUnsupported = errors.New("statfs not supported for the collector roots")
+
+// PseudoFileSystems lists the filesystem types GetFileSystemUsage skips,
+// the same kernel interfaces `df` hides by default.
+var PseudoFileSystems = map[string]bool{
+	"autofs":      true,
+	"binfmt_misc": true,
+	"bpf":         true,
+	"cgroup":      true,
+	"cgroup2":     true,
+	"configfs":    true,
+	"debugfs":     true,
+	"devpts":      true,
+	"efivarfs":    true,
+	"fusectl":     true,
+	"hugetlbfs":   true,
+	"mqueue":      true,
+	"nsfs":        true,
+	"proc":        true,
+	"pstore":      true,
+	"rpc_pipefs":  true,
+	"securityfs":  true,
+	"selinuxfs":   true,
+	"sysfs":       true,
+	"tracefs":     true,
+}
+
+// NetworkFileSystems lists the filesystem types GetFileSystemUsage skips
+// because statfs(2) on them waits, without a timeout, for a server that
+// may never answer.
+var NetworkFileSystems = map[string]bool{
+	"9p":             true,
+	"afs":            true,
+	"ceph":           true,
+	"cifs":           true,
+	"fuse.glusterfs": true,
+	"fuse.sshfs":     true,
+	"glusterfs":      true,
+	"lustre":         true,
+	"ncpfs":          true,
+	"nfs":            true,
+	"nfs4":           true,
+	"smb3":           true,
+	"smbfs":          true,
+}
+
+// _UnescapeMountPath decodes the octal escapes, such as \040 for a space,
+// the kernel uses in mountinfo paths.
+func _UnescapeMountPath(value string) string {
+	if !strings.Contains(value, "\\") {
+		return value
+	}
+	builder := new(strings.Builder)
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && i+3 < len(value) {
+			if v, err := strconv.ParseUint(value[i+1:i+4], 8, 8); nil == err {
+				builder.WriteByte(byte(v))
+				i = i + 3
+				continue
+			}
+		}
+		builder.WriteByte(value[i])
+	}
+	return builder.String()
+}
+
+func _ParseMountInfo(data []byte) (Mounts, error) {
+	var (
+		newline = []byte("\n")
+		mounts  = make(Mounts, 0)
+	)
+	lines := bytes.Split(data, newline)
+	for _, line := range lines {
+		fields := bytes.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		separator := -1
+		for i := 6; i < len(fields); i++ {
+			if string(fields[i]) == "-" {
+				separator = i
+				break
+			}
+		}
+		if separator < 0 || len(fields) < separator+3 {
+			return nil, errors.New("incorrectly formatted mountinfo content")
+		}
+		mount := Mount{
+			Root:           _UnescapeMountPath(string(fields[3])),
+			MountPoint:     _UnescapeMountPath(string(fields[4])),
+			Options:        strings.Split(string(fields[5]), ","),
+			OptionalFields: make([]string, 0),
+			FileSystemType: string(fields[separator+1]),
+			Source:         _UnescapeMountPath(string(fields[separator+2])),
+			SuperOptions:   make([]string, 0),
+		}
+		if v, err := strconv.ParseInt(FastBytesToString(fields[0]), 10, 64); nil != err {
+			return nil, err
+		} else {
+			mount.MountId = v
+		}
+		if v, err := strconv.ParseInt(FastBytesToString(fields[1]), 10, 64); nil != err {
+			return nil, err
+		} else {
+			mount.ParentId = v
+		}
+		device := strings.SplitN(string(fields[2]), ":", 2)
+		if len(device) != 2 {
+			return nil, errors.New("incorrectly formatted mountinfo content")
+		}
+		if v, err := strconv.ParseInt(device[0], 10, 64); nil != err {
+			return nil, err
+		} else {
+			mount.Major = v
+		}
+		if v, err := strconv.ParseInt(device[1], 10, 64); nil != err {
+			return nil, err
+		} else {
+			mount.Minor = v
+		}
+		for _, field := range fields[6:separator] {
+			mount.OptionalFields = append(mount.OptionalFields, string(field))
+		}
+		if len(fields) > separator+3 {
+			mount.SuperOptions = strings.Split(string(fields[separator+3]), ",")
+		}
+		mounts = append(mounts, mount)
+	}
+	return mounts, nil
+}
+
+func GetMounts() (Mounts, error) {
+	return DefaultCollector.GetMounts()
+}
+
+func (c *Collector) GetMounts() (Mounts, error) {
+	contents, err := c._ReadProcFile(MountInfoFile)
+	if nil != err {
+		return nil, err
+	}
+	return _ParseMountInfo(contents)
+}
+
+func GetFileSystemUsage() (FileSystemUsages, error) {
+	return DefaultCollector.GetFileSystemUsage()
+}
+
+// _StatFS queries the filesystem mounted at mountPoint. statfs(2) only sees
+// the live mount namespace, so it is used only when the collector reads the
+// host's own /proc; a custom FS may provide a StatFS(name) method taking the
+// mount point relative to its root instead, like ReadLink.
+func (c *Collector) _StatFS(mountPoint string) (*FileSystemStat, error) {
+	if fsys, ok := c.FS.(interface {
+		StatFS(name string) (*FileSystemStat, error)
+	}); ok {
+		return fsys.StatFS(_Resolve("/", "/", mountPoint))
+	}
+	if nil == c.FS && c._ProcPath() == _Resolve(ProcDirectory, ProcDirectory) {
+		return GetFileSystemStat(mountPoint)
+	}
+	return nil, ErrStatFSUnsupported
+}
+
+// GetFileSystemUsage returns the usage of every mount that is neither one of
+// the PseudoFileSystems nor one of the NetworkFileSystems. Mounts that
+// cannot be queried are skipped. Collectors reading another /proc, such as
+// a host /proc mounted in a container, fail with ErrStatFSUnsupported unless
+// their FS provides StatFS, rather than report the filesystems of the wrong
+// mount namespace.
+func (c *Collector) GetFileSystemUsage() (FileSystemUsages, error) {
+	return c._GetFileSystemUsage(false)
+}
+
+func GetAllFileSystemUsage() (FileSystemUsages, error) {
+	return DefaultCollector.GetAllFileSystemUsage()
+}
+
+// GetAllFileSystemUsage is GetFileSystemUsage including the
+// NetworkFileSystems. It blocks for as long as any of their servers does not
+// answer.
+func (c *Collector) GetAllFileSystemUsage() (FileSystemUsages, error) {
+	return c._GetFileSystemUsage(true)
+}
+
+func (c *Collector) _GetFileSystemUsage(network bool) (FileSystemUsages, error) {
+	mounts, err := c.GetMounts()
+	if nil != err {
+		return nil, err
+	}
+	usage := make(FileSystemUsages, 0, len(mounts))
+	for _, mount := range mounts {
+		if PseudoFileSystems[mount.FileSystemType] {
+			continue
+		}
+		if !network && NetworkFileSystems[mount.FileSystemType] {
+			continue
+		}
+		stat, err := c._StatFS(mount.MountPoint)
+		if errors.Is(err, ErrStatFSUnsupported) {
+			return nil, err
+		}
+		if nil != err {
+			continue
+		}
+		usage = append(usage, FileSystemUsage{
+			Mount: mount,
+			Stat:  *stat,
+		})
+	}
+	return usage, nil
+}
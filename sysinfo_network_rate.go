@@ -0,0 +1,114 @@
+package sysinfo_go
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// _CounterIncrease returns the increase of an unsigned kernel counter. A
+// counter below its previous value either wrapped, at 32 bits for drivers
+// keeping 32 bit statistics or at 64 bits, or was reset by an interface
+// reset or driver reload, in which case the new value is the increase. A 32
+// bit wrap is only assumed when the counter got further past zero than it
+// was short of the limit, so a reset from 3 GB to 1 KB is not reported as
+// 1.3 GB of traffic.
+func _CounterIncrease(previous, current int64) uint64 {
+	var (
+		last = uint64(previous)
+		now  = uint64(current)
+	)
+	switch {
+	case now >= last:
+		return now - last
+	case last <= math.MaxUint32 && math.MaxUint32-last < now:
+		return math.MaxUint32 - last + now + 1
+	case last <= math.MaxUint32:
+		return now
+	case now-last < 1<<63:
+		return now - last
+	default:
+		return now
+	}
+}
+
+func _CounterRate(previous, current int64, seconds float64) float64 {
+	return float64(_CounterIncrease(previous, current)) / seconds
+}
+
+// CalculateNetworkRates returns per second rates for interfaces present in
+// both snapshots. Interfaces that appeared or disappeared in between are
+// left out.
+func CalculateNetworkRates(previous, current NetworkStats, interval time.Duration) NetworkRates {
+	rates := make(NetworkRates, 0, len(current))
+	if interval <= 0 {
+		return rates
+	}
+	var (
+		seconds    = interval.Seconds()
+		interfaces = make(map[string]*NetworkStat)
+	)
+	for i := range previous {
+		interfaces[previous[i].Interface] = &previous[i]
+	}
+	for i := range current {
+		stat := &current[i]
+		last, ok := interfaces[stat.Interface]
+		if !ok {
+			continue
+		}
+		rates = append(rates, NetworkRate{
+			Interface:          stat.Interface,
+			ReceivedBytes:      _CounterRate(last.ReceivedBytes, stat.ReceivedBytes, seconds),
+			ReceivedPackets:    _CounterRate(last.ReceivedPackets, stat.ReceivedPackets, seconds),
+			ReceivedErrors:     _CounterRate(last.ReceivedErrors, stat.ReceivedErrors, seconds),
+			ReceivedDropped:    _CounterRate(last.ReceivedDropped, stat.ReceivedDropped, seconds),
+			TransmittedBytes:   _CounterRate(last.TransmittedBytes, stat.TransmittedBytes, seconds),
+			TransmittedPackets: _CounterRate(last.TransmittedPackets, stat.TransmittedPackets, seconds),
+			TransmittedErrors:  _CounterRate(last.TransmittedErrors, stat.TransmittedErrors, seconds),
+			TransmittedDropped: _CounterRate(last.TransmittedDropped, stat.TransmittedDropped, seconds),
+		})
+	}
+	return rates
+}
+
+// NetworkRateSampler reports interface throughput between successive
+// NetworkStats snapshots.
+type NetworkRateSampler struct {
+	collector *Collector
+	mutex     sync.Mutex
+	previous  NetworkStats
+	time      time.Time
+}
+
+func NewNetworkRateSampler(collector *Collector) *NetworkRateSampler {
+	if nil == collector {
+		collector = DefaultCollector
+	}
+	return &NetworkRateSampler{
+		collector: collector,
+	}
+}
+
+// Sample reads /proc/net/dev and returns the rates since the previous
+// snapshot. The first call only records the snapshot and returns no rates.
+func (s *NetworkRateSampler) Sample() (NetworkRates, error) {
+	stats, err := s.collector.GetNetworkStats()
+	if nil != err {
+		return nil, err
+	}
+	return s.Update(stats, time.Now()), nil
+}
+
+// Update records stats taken at the given time and returns the rates since
+// the previous snapshot.
+func (s *NetworkRateSampler) Update(stats NetworkStats, at time.Time) NetworkRates {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	rates := make(NetworkRates, 0)
+	if !s.time.IsZero() {
+		rates = CalculateNetworkRates(s.previous, stats, at.Sub(s.time))
+	}
+	s.previous, s.time = stats, at
+	return rates
+}
@@ -0,0 +1,151 @@
+package sysinfo_go
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"strconv"
+)
+
+const (
+	PressureDirectory = "/proc/pressure"
+	CgroupDirectory   = "/sys/fs/cgroup"
+)
+
+const (
+	PressureCPU    = "cpu"
+	PressureMemory = "memory"
+	PressureIO     = "io"
+	PressureIRQ    = "irq"
+)
+
+const (
+	PressureSome   = "some"
+	PressureFull   = "full"
+	PressureAvg10  = "avg10"
+	PressureAvg60  = "avg60"
+	PressureAvg300 = "avg300"
+	PressureTotal  = "total"
+)
+
+// _ParsePressure parses the format shared by /proc/pressure/* and the
+// cgroup v2 *.pressure files. Total stall time is in microseconds.
+func _ParsePressure(data []byte) (*Pressure, error) {
+	var (
+		newline  = []byte("\n")
+		equal    = []byte("=")
+		pressure = new(Pressure)
+	)
+	lines := bytes.Split(data, newline)
+	for _, line := range lines {
+		fields := bytes.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		stall := new(PressureStall)
+		for _, field := range fields[1:] {
+			items := bytes.Split(field, equal)
+			if len(items) != 2 {
+				return nil, errors.New("incorrectly formatted pressure content")
+			}
+			value := FastBytesToString(items[1])
+			switch FastBytesToString(items[0]) {
+			case PressureAvg10:
+				if v, err := strconv.ParseFloat(value, 64); nil != err {
+					return nil, err
+				} else {
+					stall.Avg10 = v
+				}
+			case PressureAvg60:
+				if v, err := strconv.ParseFloat(value, 64); nil != err {
+					return nil, err
+				} else {
+					stall.Avg60 = v
+				}
+			case PressureAvg300:
+				if v, err := strconv.ParseFloat(value, 64); nil != err {
+					return nil, err
+				} else {
+					stall.Avg300 = v
+				}
+			case PressureTotal:
+				if v, err := strconv.ParseInt(value, 10, 64); nil != err {
+					return nil, err
+				} else {
+					stall.Total = v
+				}
+			default:
+				// Do Nothing
+			}
+		}
+		switch FastBytesToString(fields[0]) {
+		case PressureSome:
+			pressure.Some = stall
+		case PressureFull:
+			pressure.Full = stall
+		default:
+			return nil, errors.New("incorrectly formatted pressure content")
+		}
+	}
+	return pressure, nil
+}
+
+// _ReadPressures reads one pressure file per resource, leaving resources
+// the kernel does not report, such as irq before Linux 6.1, nil.
+func _ReadPressures(read func(resource string) ([]byte, error)) (*PressureStats, error) {
+	var (
+		stats     = new(PressureStats)
+		resources = []struct {
+			name     string
+			pressure **Pressure
+		}{
+			{PressureCPU, &stats.CPU},
+			{PressureMemory, &stats.Memory},
+			{PressureIO, &stats.IO},
+			{PressureIRQ, &stats.IRQ},
+		}
+		found = false
+	)
+	for _, resource := range resources {
+		contents, err := read(resource.name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if nil != err {
+			return nil, err
+		}
+		pressure, err := _ParsePressure(contents)
+		if nil != err {
+			return nil, err
+		}
+		*resource.pressure = pressure
+		found = true
+	}
+	if !found {
+		return nil, errors.New("pressure stall information not available")
+	}
+	return stats, nil
+}
+
+func GetPressure() (*PressureStats, error) {
+	return DefaultCollector.GetPressure()
+}
+
+func (c *Collector) GetPressure() (*PressureStats, error) {
+	return _ReadPressures(func(resource string) ([]byte, error) {
+		return c._ReadProcFile(PressureDirectory, resource)
+	})
+}
+
+func GetCgroupPressure(cgroup string) (*PressureStats, error) {
+	return DefaultCollector.GetCgroupPressure(cgroup)
+}
+
+// GetCgroupPressure reads the *.pressure files of a cgroup v2 group, given
+// as its path below the cgroup mount such as "/system.slice".
+func (c *Collector) GetCgroupPressure(cgroup string) (*PressureStats, error) {
+	directory := c._CgroupV2Directory()
+	return _ReadPressures(func(resource string) ([]byte, error) {
+		return c._ReadSysFile(directory, cgroup, resource+".pressure")
+	})
+}
@@ -0,0 +1,240 @@
+package sysinfo_go
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	ProcessStatFile    = "stat"
+	ProcessStatusFile  = "status"
+	ProcessCmdLineFile = "cmdline"
+	ProcessCommFile    = "comm"
+	ProcessExeLink     = "exe"
+	ProcessCwdLink     = "cwd"
+)
+
+const (
+	ProcessStatusUid = "Uid"
+	ProcessStatusGid = "Gid"
+)
+
+// ClockTicks is USER_HZ, the unit of the time fields in /proc/[pid]/stat
+// and /proc/stat. It is assumed to be 100, the value sysconf(_SC_CLK_TCK)
+// returns on every common architecture, rather than queried, since that
+// needs cgo; kernels built with a different USER_HZ scale times wrongly.
+const ClockTicks = 100
+
+var ErrProcessNotFound = errors.New("process not found")
+
+// _ProcessError maps the errors seen when a process exits between reads of
+// its /proc entries onto ErrProcessNotFound.
+func _ProcessError(err error) error {
+	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, syscall.ESRCH) {
+		return ErrProcessNotFound
+	}
+	return err
+}
+
+func _ParseProcessStat(data []byte, bootTime int64) (*Process, error) {
+	var (
+		begin = bytes.IndexByte(data, '(')
+		end   = bytes.LastIndexByte(data, ')')
+	)
+	if begin < 0 || end < begin {
+		return nil, errors.New("incorrectly formatted process stat content")
+	}
+	pid, err := strconv.Atoi(FastBytesToString(bytes.TrimSpace(data[:begin])))
+	if nil != err {
+		return nil, err
+	}
+	fields := bytes.Fields(data[end+1:])
+	if len(fields) < 22 {
+		return nil, errors.New("incorrectly formatted process stat content")
+	}
+	// fields[0] is the third column of the file, see proc(5). Only the
+	// columns up to rss are read; later unsigned ones such as rsslim may
+	// overflow int64.
+	values := make([]int64, 22)
+	for i := 1; i < len(values); i++ {
+		v, err := strconv.ParseInt(FastBytesToString(fields[i]), 10, 64)
+		if nil != err {
+			return nil, err
+		}
+		values[i] = v
+	}
+	var (
+		start = time.Duration(values[19]) * (time.Second / ClockTicks)
+		page  = int64(os.Getpagesize())
+	)
+	process := &Process{
+		Pid:          pid,
+		ParentPid:    int(values[1]),
+		Name:         string(data[begin+1 : end]),
+		State:        string(fields[0]),
+		Uid:          -1,
+		EffectiveUid: -1,
+		Gid:          -1,
+		EffectiveGid: -1,
+		MinorFaults:  values[7],
+		MajorFaults:  values[9],
+		UserTime:     float64(values[11]) / ClockTicks,
+		SystemTime:   float64(values[12]) / ClockTicks,
+		Priority:     values[15],
+		Nice:         values[16],
+		Threads:      values[17],
+		StartTime:    time.Unix(bootTime, 0).Add(start),
+		VirtualSize:  values[20],
+		ResidentSize: values[21] * page,
+	}
+	return process, nil
+}
+
+func _ParseProcessStatus(data []byte, process *Process) error {
+	var (
+		newline = []byte("\n")
+		colon   = []byte(":")
+	)
+	lines := bytes.Split(data, newline)
+	for _, line := range lines {
+		if len(line) == 0 {
+			continue
+		}
+		items := bytes.SplitN(line, colon, 2)
+		if len(items) != 2 {
+			return errors.New("incorrectly formatted process status content")
+		}
+		var (
+			key   = FastBytesToString(bytes.TrimSpace(items[0]))
+			value = bytes.Fields(items[1])
+		)
+		switch key {
+		case ProcessStatusUid, ProcessStatusGid:
+			if len(value) < 2 {
+				return errors.New("incorrectly formatted process status content")
+			}
+			actual, err := strconv.ParseInt(FastBytesToString(value[0]), 10, 64)
+			if nil != err {
+				return err
+			}
+			effective, err := strconv.ParseInt(FastBytesToString(value[1]), 10, 64)
+			if nil != err {
+				return err
+			}
+			if key == ProcessStatusUid {
+				process.Uid, process.EffectiveUid = actual, effective
+			} else {
+				process.Gid, process.EffectiveGid = actual, effective
+			}
+		default:
+			// Do Nothing
+		}
+	}
+	return nil
+}
+
+func _ParseProcessCmdLine(data []byte) []string {
+	args := make([]string, 0)
+	data = bytes.TrimRight(data, "\x00")
+	if len(data) == 0 {
+		return args
+	}
+	for _, arg := range bytes.Split(data, []byte{0}) {
+		args = append(args, string(arg))
+	}
+	return args
+}
+
+func (c *Collector) _BootTime() (int64, error) {
+	stat, err := c.GetStat()
+	if nil != err {
+		return 0, err
+	}
+	return stat.BootTime, nil
+}
+
+func (c *Collector) _GetProcess(pid int, bootTime int64) (*Process, error) {
+	directory := strconv.Itoa(pid)
+	contents, err := c._ReadProcFile(directory, ProcessStatFile)
+	if nil != err {
+		return nil, _ProcessError(err)
+	}
+	process, err := _ParseProcessStat(contents, bootTime)
+	if nil != err {
+		return nil, err
+	}
+	contents, err = c._ReadProcFile(directory, ProcessStatusFile)
+	if nil != err {
+		return nil, _ProcessError(err)
+	}
+	if err := _ParseProcessStatus(contents, process); nil != err {
+		return nil, err
+	}
+	contents, err = c._ReadProcFile(directory, ProcessCmdLineFile)
+	if nil != err {
+		return nil, _ProcessError(err)
+	}
+	process.CommandLine = _ParseProcessCmdLine(contents)
+	contents, err = c._ReadProcFile(directory, ProcessCommFile)
+	if nil != err {
+		return nil, _ProcessError(err)
+	}
+	process.Name = string(bytes.TrimRight(contents, "\n"))
+	// Links of kernel threads and of other users' processes are unreadable,
+	// leave them empty rather than failing.
+	if link, err := c._ReadLink(c._ProcPath(directory, ProcessExeLink)); nil == err {
+		process.Executable = link
+	}
+	if link, err := c._ReadLink(c._ProcPath(directory, ProcessCwdLink)); nil == err {
+		process.WorkingDirectory = link
+	}
+	return process, nil
+}
+
+func GetProcess(pid int) (*Process, error) {
+	return DefaultCollector.GetProcess(pid)
+}
+
+// GetProcess returns ErrProcessNotFound when pid does not exist or exits
+// while its entries are being read.
+func (c *Collector) GetProcess(pid int) (*Process, error) {
+	bootTime, err := c._BootTime()
+	if nil != err {
+		return nil, err
+	}
+	return c._GetProcess(pid, bootTime)
+}
+
+func GetProcesses() (Processes, error) {
+	return DefaultCollector.GetProcesses()
+}
+
+// GetProcesses returns every process listed by ListProcessId, skipping the
+// ones that exit before they can be read.
+func (c *Collector) GetProcesses() (Processes, error) {
+	pids, err := c.ListProcessId()
+	if nil != err {
+		return nil, err
+	}
+	bootTime, err := c._BootTime()
+	if nil != err {
+		return nil, err
+	}
+	processes := make(Processes, 0, len(pids))
+	for _, pid := range pids {
+		process, err := c._GetProcess(pid, bootTime)
+		if errors.Is(err, ErrProcessNotFound) {
+			continue
+		}
+		if nil != err {
+			return nil, err
+		}
+		processes = append(processes, *process)
+	}
+	return processes, nil
+}
@@ -0,0 +1,568 @@
+package sysinfo_go
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	MetricCounter = "counter"
+	MetricGauge   = "gauge"
+)
+
+const (
+	PrometheusNamespace   = "sysinfo"
+	PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// _CounterValue reads kernel counters stored as int64 but printed unsigned.
+func _CounterValue(value int64) float64 {
+	return float64(uint64(value))
+}
+
+func _Gauge(name, help string, value float64, labels ...MetricLabel) MetricFamily {
+	return MetricFamily{
+		Name:    name,
+		Help:    help,
+		Type:    MetricGauge,
+		Metrics: []Metric{{Labels: labels, Value: value}},
+	}
+}
+
+func _Counter(name, help string, value float64, labels ...MetricLabel) MetricFamily {
+	return MetricFamily{
+		Name:    name,
+		Help:    help,
+		Type:    MetricCounter,
+		Metrics: []Metric{{Labels: labels, Value: value}},
+	}
+}
+
+func _CPUStatsMetrics(stats []CPUStat) MetricFamilies {
+	cpu := MetricFamily{
+		Name:    "cpu_seconds_total",
+		Help:    "Seconds the CPUs spent in each mode.",
+		Type:    MetricCounter,
+		Metrics: make([]Metric, 0),
+	}
+	guest := MetricFamily{
+		Name:    "cpu_guest_seconds_total",
+		Help:    "Seconds the CPUs spent running virtual CPUs of guests.",
+		Type:    MetricCounter,
+		Metrics: make([]Metric, 0),
+	}
+	for _, it := range stats {
+		if it.CPUId == StatCPU {
+			continue
+		}
+		id := strings.TrimPrefix(it.CPUId, StatCPU)
+		// Guest time is already included in user and nice.
+		for _, mode := range []struct {
+			name  string
+			ticks int64
+		}{
+			{"user", it.User - it.Guest},
+			{"nice", it.Nice - it.GuestNice},
+			{"system", it.System},
+			{"idle", it.Idle},
+			{"iowait", it.IOWait},
+			{"irq", it.IRQ},
+			{"softirq", it.SoftIRQ},
+			{"steal", it.Steal},
+		} {
+			cpu.Metrics = append(cpu.Metrics, Metric{
+				Labels: []MetricLabel{{"cpu", id}, {"mode", mode.name}},
+				Value:  float64(mode.ticks) / ClockTicks,
+			})
+		}
+		guest.Metrics = append(guest.Metrics,
+			Metric{Labels: []MetricLabel{{"cpu", id}, {"mode", "user"}}, Value: float64(it.Guest) / ClockTicks},
+			Metric{Labels: []MetricLabel{{"cpu", id}, {"mode", "nice"}}, Value: float64(it.GuestNice) / ClockTicks},
+		)
+	}
+	return MetricFamilies{cpu, guest}
+}
+
+func _StatMetrics(stat *Stat) MetricFamilies {
+	softirqs := MetricFamily{
+		Name:    "softirqs_total",
+		Help:    "Softirqs handled by type.",
+		Type:    MetricCounter,
+		Metrics: make([]Metric, 0),
+	}
+	for _, it := range []struct {
+		name  string
+		count int64
+	}{
+		{"hi", stat.SoftIRQs.HI},
+		{"timer", stat.SoftIRQs.Timer},
+		{"net_tx", stat.SoftIRQs.NetTx},
+		{"net_rx", stat.SoftIRQs.NetRx},
+		{"block", stat.SoftIRQs.Block},
+		{"irq_poll", stat.SoftIRQs.IRQPoll},
+		{"tasklet", stat.SoftIRQs.Tasklet},
+		{"sched", stat.SoftIRQs.Sched},
+		{"hrtimer", stat.SoftIRQs.HRTimer},
+		{"rcu", stat.SoftIRQs.RCU},
+	} {
+		softirqs.Metrics = append(softirqs.Metrics, Metric{
+			Labels: []MetricLabel{{"type", it.name}},
+			Value:  _CounterValue(it.count),
+		})
+	}
+	return append(_CPUStatsMetrics(stat.CPUStats),
+		_Gauge("boot_time_seconds", "System boot time in seconds since the epoch.", float64(stat.BootTime)),
+		_Counter("context_switches_total", "Context switches.", _CounterValue(stat.ContextSwitches)),
+		_Counter("interrupts_total", "Interrupts serviced.", _CounterValue(stat.Interrupts)),
+		softirqs,
+		_Counter("forks_total", "Processes and threads created.", _CounterValue(stat.Processes)),
+		_Gauge("procs_running", "Processes in runnable state.", float64(stat.ProcessesRunning)),
+		_Gauge("procs_blocked", "Processes blocked waiting for I/O.", float64(stat.ProcessesBlocked)),
+	)
+}
+
+func _MemInfoMetrics(mem *MemInfo) MetricFamilies {
+	families := make(MetricFamilies, 0)
+	for _, it := range []struct {
+		name  string
+		help  string
+		value int64
+	}{
+		{"memory_total_bytes", "Usable RAM.", mem.Total},
+		{"memory_free_bytes", "Unused RAM.", mem.Free},
+		{"memory_available_bytes", "RAM available for starting new applications without swapping.", mem.Available},
+		{"memory_buffers_bytes", "RAM used for block device buffers.", mem.Buffered},
+		{"memory_cached_bytes", "RAM used for the page cache.", mem.Cached},
+		{"memory_swap_cached_bytes", "Swapped out memory that is also in RAM.", mem.SwapCached},
+		{"memory_active_bytes", "Memory used recently.", mem.Active},
+		{"memory_inactive_bytes", "Memory not used recently.", mem.Inactive},
+		{"memory_active_anon_bytes", "Anonymous memory used recently.", mem.ActiveAnon},
+		{"memory_inactive_anon_bytes", "Anonymous memory not used recently.", mem.InactiveAnon},
+		{"memory_active_file_bytes", "Page cache used recently.", mem.ActiveFile},
+		{"memory_inactive_file_bytes", "Page cache not used recently.", mem.InactiveFile},
+		{"memory_unevictable_bytes", "Memory that cannot be reclaimed.", mem.Unevictable},
+		{"memory_mlocked_bytes", "Memory locked with mlock.", mem.Mlocked},
+		{"memory_swap_total_bytes", "Swap space.", mem.SwapTotal},
+		{"memory_swap_free_bytes", "Unused swap space.", mem.SwapFree},
+		{"memory_dirty_bytes", "Memory waiting to be written back to disk.", mem.Dirty},
+		{"memory_writeback_bytes", "Memory being written back to disk.", mem.Writeback},
+		{"memory_anon_pages_bytes", "Anonymous memory mapped into page tables.", mem.AnonPages},
+		{"memory_mapped_bytes", "Files mapped into memory.", mem.Mapped},
+		{"memory_shmem_bytes", "Shared memory and tmpfs.", mem.Shmem},
+		{"memory_slab_bytes", "Kernel slab memory.", mem.Slab},
+		{"memory_slab_reclaimable_bytes", "Reclaimable kernel slab memory.", mem.SlabReclaimable},
+		{"memory_slab_unreclaimable_bytes", "Unreclaimable kernel slab memory.", mem.SlabUnreclaimable},
+		{"memory_kernel_stack_bytes", "Kernel stacks.", mem.KernelStack},
+		{"memory_page_tables_bytes", "Page tables.", mem.PageTables},
+		{"memory_commit_limit_bytes", "Memory that can be allocated under strict overcommit.", mem.CommitLimit},
+		{"memory_committed_as_bytes", "Memory allocated by all processes.", mem.CommittedAS},
+		{"memory_vmalloc_used_bytes", "Used vmalloc area.", mem.VmallocUsed},
+		{"memory_anon_huge_pages_bytes", "Anonymous transparent huge pages.", mem.AnonHugePages},
+		{"memory_hugetlb_bytes", "Memory reserved for huge pages of all sizes.", mem.Hugetlb},
+	} {
+		families = append(families, _Gauge(it.name, it.help, float64(it.value)))
+	}
+	return append(families,
+		_Gauge("memory_huge_pages_pool", "Huge pages in the pool.", float64(mem.HugePagesTotal)),
+		_Gauge("memory_huge_pages_free", "Huge pages in the pool not yet allocated.", float64(mem.HugePagesFree)),
+		_Gauge("memory_huge_pages_reserved", "Huge pages committed but not yet allocated.", float64(mem.HugePagesReserved)),
+		_Gauge("memory_huge_pages_surplus", "Huge pages above the pool size.", float64(mem.HugePagesSurplus)),
+		_Gauge("memory_huge_page_size_bytes", "Default huge page size.", float64(mem.HugePageSize)),
+	)
+}
+
+func _VMStatMetrics(vm *VMStat) MetricFamilies {
+	return MetricFamilies{
+		_Counter("vmstat_page_faults_total", "Page faults.", _CounterValue(vm.PageFault)),
+		_Counter("vmstat_major_page_faults_total", "Page faults that required disk I/O.", _CounterValue(vm.PageMajorFault)),
+		_Counter("vmstat_pages_in_total", "Pages read in from disk.", _CounterValue(vm.PageIn)),
+		_Counter("vmstat_pages_out_total", "Pages written out to disk.", _CounterValue(vm.PageOut)),
+		_Counter("vmstat_swap_in_total", "Pages swapped in.", _CounterValue(vm.SwapIn)),
+		_Counter("vmstat_swap_out_total", "Pages swapped out.", _CounterValue(vm.SwapOut)),
+		_Counter("vmstat_oom_kills_total", "Processes killed by the OOM killer.", _CounterValue(vm.OOMKill)),
+	}
+}
+
+func _LoadMetrics(load *Load) MetricFamilies {
+	return MetricFamilies{
+		_Gauge("load1", "Load average over 1 minute.", load.Load1),
+		_Gauge("load5", "Load average over 5 minutes.", load.Load5),
+		_Gauge("load15", "Load average over 15 minutes.", load.Load15),
+		_Gauge("scheduling_entities_runnable", "Runnable processes and threads.", float64(load.Runnable)),
+		_Gauge("scheduling_entities", "Processes and threads.", float64(load.Entities)),
+	}
+}
+
+func _DiskStatsMetrics(stats DiskStats) MetricFamilies {
+	families := MetricFamilies{
+		{Name: "disk_reads_completed_total", Help: "Reads completed.", Type: MetricCounter},
+		{Name: "disk_reads_merged_total", Help: "Adjacent reads merged.", Type: MetricCounter},
+		{Name: "disk_read_bytes_total", Help: "Bytes read.", Type: MetricCounter},
+		{Name: "disk_read_time_seconds_total", Help: "Seconds spent by reads.", Type: MetricCounter},
+		{Name: "disk_writes_completed_total", Help: "Writes completed.", Type: MetricCounter},
+		{Name: "disk_writes_merged_total", Help: "Adjacent writes merged.", Type: MetricCounter},
+		{Name: "disk_written_bytes_total", Help: "Bytes written.", Type: MetricCounter},
+		{Name: "disk_write_time_seconds_total", Help: "Seconds spent by writes.", Type: MetricCounter},
+		{Name: "disk_io_now", Help: "I/Os in progress.", Type: MetricGauge},
+		{Name: "disk_io_time_seconds_total", Help: "Seconds the device was busy.", Type: MetricCounter},
+		{Name: "disk_io_time_weighted_seconds_total", Help: "Seconds spent by I/Os, weighted by I/Os in progress.", Type: MetricCounter},
+		{Name: "disk_discards_completed_total", Help: "Discards completed.", Type: MetricCounter},
+		{Name: "disk_discards_merged_total", Help: "Adjacent discards merged.", Type: MetricCounter},
+		{Name: "disk_discarded_bytes_total", Help: "Bytes discarded.", Type: MetricCounter},
+		{Name: "disk_discard_time_seconds_total", Help: "Seconds spent by discards.", Type: MetricCounter},
+	}
+	for _, stat := range stats {
+		labels := []MetricLabel{{"device", stat.Device}}
+		for i, value := range []float64{
+			_CounterValue(stat.ReadsComplete),
+			_CounterValue(stat.ReadsMerged),
+			_CounterValue(stat.SectorsRead) * DiskStatSectorSize,
+			_CounterValue(stat.ReadingTime) / 1000,
+			_CounterValue(stat.WritesComplete),
+			_CounterValue(stat.WritesMerged),
+			_CounterValue(stat.SectorsWritten) * DiskStatSectorSize,
+			_CounterValue(stat.WritingTime) / 1000,
+			float64(stat.IOInProgess),
+			_CounterValue(stat.TotalIOTime) / 1000,
+			_CounterValue(stat.WeightedIOTime) / 1000,
+			_CounterValue(stat.DiscardsComplete),
+			_CounterValue(stat.DiscardsMerged),
+			_CounterValue(stat.SectorsDiscarded) * DiskStatSectorSize,
+			_CounterValue(stat.DiscardingTime) / 1000,
+		} {
+			families[i].Metrics = append(families[i].Metrics, Metric{Labels: labels, Value: value})
+		}
+	}
+	return families
+}
+
+func _NetworkStatsMetrics(stats NetworkStats) MetricFamilies {
+	families := MetricFamilies{
+		{Name: "network_receive_bytes_total", Help: "Bytes received.", Type: MetricCounter},
+		{Name: "network_receive_packets_total", Help: "Packets received.", Type: MetricCounter},
+		{Name: "network_receive_errors_total", Help: "Receive errors.", Type: MetricCounter},
+		{Name: "network_receive_drop_total", Help: "Received packets dropped.", Type: MetricCounter},
+		{Name: "network_receive_fifo_total", Help: "Receive FIFO buffer errors.", Type: MetricCounter},
+		{Name: "network_receive_frame_total", Help: "Receive framing errors.", Type: MetricCounter},
+		{Name: "network_receive_compressed_total", Help: "Compressed packets received.", Type: MetricCounter},
+		{Name: "network_receive_multicast_total", Help: "Multicast frames received.", Type: MetricCounter},
+		{Name: "network_transmit_bytes_total", Help: "Bytes transmitted.", Type: MetricCounter},
+		{Name: "network_transmit_packets_total", Help: "Packets transmitted.", Type: MetricCounter},
+		{Name: "network_transmit_errors_total", Help: "Transmit errors.", Type: MetricCounter},
+		{Name: "network_transmit_drop_total", Help: "Transmitted packets dropped.", Type: MetricCounter},
+		{Name: "network_transmit_fifo_total", Help: "Transmit FIFO buffer errors.", Type: MetricCounter},
+		{Name: "network_transmit_colls_total", Help: "Collisions detected.", Type: MetricCounter},
+		{Name: "network_transmit_carrier_total", Help: "Carrier losses.", Type: MetricCounter},
+		{Name: "network_transmit_compressed_total", Help: "Compressed packets transmitted.", Type: MetricCounter},
+	}
+	for _, stat := range stats {
+		labels := []MetricLabel{{"interface", stat.Interface}}
+		for i, value := range []int64{
+			stat.ReceivedBytes,
+			stat.ReceivedPackets,
+			stat.ReceivedErrors,
+			stat.ReceivedDropped,
+			stat.ReceivedFIFO,
+			stat.ReceivedFrame,
+			stat.ReceivedCompressed,
+			stat.ReceivedMulticast,
+			stat.TransmittedBytes,
+			stat.TransmittedPackets,
+			stat.TransmittedErrors,
+			stat.TransmittedDropped,
+			stat.TransmittedFIFO,
+			stat.TransmittedCollisions,
+			stat.TransmittedCarrier,
+			stat.TransmittedCompressed,
+		} {
+			families[i].Metrics = append(families[i].Metrics, Metric{Labels: labels, Value: _CounterValue(value)})
+		}
+	}
+	return families
+}
+
+func _FileSystemUsageMetrics(usage FileSystemUsages) MetricFamilies {
+	families := MetricFamilies{
+		{Name: "filesystem_size_bytes", Help: "Filesystem size.", Type: MetricGauge},
+		{Name: "filesystem_free_bytes", Help: "Free space, including space reserved for root.", Type: MetricGauge},
+		{Name: "filesystem_avail_bytes", Help: "Space available to unprivileged users.", Type: MetricGauge},
+		{Name: "filesystem_files", Help: "Inodes.", Type: MetricGauge},
+		{Name: "filesystem_files_free", Help: "Free inodes.", Type: MetricGauge},
+		{Name: "filesystem_readonly", Help: "Whether the filesystem is mounted read only.", Type: MetricGauge},
+	}
+	// A mount point mounted over reports the filesystem on top, so only the
+	// last mount of each mount point is kept to avoid duplicate series.
+	last := make(map[string]int)
+	for i, it := range usage {
+		last[it.Mount.MountPoint] = i
+	}
+	for i, it := range usage {
+		if last[it.Mount.MountPoint] != i {
+			continue
+		}
+		labels := make([]MetricLabel, 0, 3)
+		// Stats encoded without their mount have no labels.
+		for _, label := range []MetricLabel{
+			{"device", it.Mount.Source},
+			{"mountpoint", it.Mount.MountPoint},
+			{"fstype", it.Mount.FileSystemType},
+		} {
+			if len(label.Value) > 0 {
+				labels = append(labels, label)
+			}
+		}
+		readOnly := 0.0
+		if it.Stat.ReadOnly {
+			readOnly = 1
+		}
+		for i, value := range []float64{
+			float64(it.Stat.Capacity),
+			float64(it.Stat.Free),
+			float64(it.Stat.Available),
+			float64(it.Stat.Files),
+			float64(it.Stat.FreeFiles),
+			readOnly,
+		} {
+			families[i].Metrics = append(families[i].Metrics, Metric{Labels: labels, Value: value})
+		}
+	}
+	return families
+}
+
+func _PressureMetrics(stats *PressureStats) MetricFamilies {
+	families := make(MetricFamilies, 0)
+	for _, it := range []struct {
+		name     string
+		pressure *Pressure
+	}{
+		{PressureCPU, stats.CPU},
+		{PressureMemory, stats.Memory},
+		{PressureIO, stats.IO},
+		{PressureIRQ, stats.IRQ},
+	} {
+		if nil == it.pressure {
+			continue
+		}
+		if nil != it.pressure.Some {
+			families = append(families, _Counter(
+				"pressure_"+it.name+"_waiting_seconds_total",
+				"Seconds some tasks were stalled waiting for "+it.name+".",
+				float64(it.pressure.Some.Total)/1e6,
+			))
+		}
+		if nil != it.pressure.Full {
+			families = append(families, _Counter(
+				"pressure_"+it.name+"_stalled_seconds_total",
+				"Seconds all non-idle tasks were stalled waiting for "+it.name+".",
+				float64(it.pressure.Full.Total)/1e6,
+			))
+		}
+	}
+	return families
+}
+
+// PrometheusExporter renders every collector in the Prometheus text
+// exposition format and serves it over HTTP.
+type PrometheusExporter struct {
+	collector *Collector
+	namespace string
+}
+
+// NewPrometheusExporter returns an exporter reading from collector, nil for
+// DefaultCollector, that prefixes metric names with namespace, empty for
+// PrometheusNamespace.
+func NewPrometheusExporter(collector *Collector, namespace string) *PrometheusExporter {
+	if nil == collector {
+		collector = DefaultCollector
+	}
+	if len(namespace) == 0 {
+		namespace = PrometheusNamespace
+	}
+	return &PrometheusExporter{
+		collector: collector,
+		namespace: namespace,
+	}
+}
+
+// Gather reads every collector. A collector that fails, such as pressure on
+// kernels without PSI, is left out and reported by the
+// scrape_collector_success gauge instead of failing the whole scrape.
+func (e *PrometheusExporter) Gather() MetricFamilies {
+	var (
+		c       = e.collector
+		sources = []struct {
+			name   string
+			gather func() (MetricFamilies, error)
+		}{
+			{"stat", func() (MetricFamilies, error) {
+				stat, err := c.GetStat()
+				if nil != err {
+					return nil, err
+				}
+				return _StatMetrics(stat), nil
+			}},
+			{"meminfo", func() (MetricFamilies, error) {
+				mem, err := c.GetMemInfo()
+				if nil != err {
+					return nil, err
+				}
+				return _MemInfoMetrics(mem), nil
+			}},
+			{"vmstat", func() (MetricFamilies, error) {
+				vm, err := c.GetVmStat()
+				if nil != err {
+					return nil, err
+				}
+				return _VMStatMetrics(vm), nil
+			}},
+			{"loadavg", func() (MetricFamilies, error) {
+				load, err := c.GetLoadAvg()
+				if nil != err {
+					return nil, err
+				}
+				return _LoadMetrics(load), nil
+			}},
+			{"diskstats", func() (MetricFamilies, error) {
+				stats, err := c.GetDiskStats()
+				if nil != err {
+					return nil, err
+				}
+				return _DiskStatsMetrics(stats), nil
+			}},
+			{"netdev", func() (MetricFamilies, error) {
+				stats, err := c.GetNetworkStats()
+				if nil != err {
+					return nil, err
+				}
+				return _NetworkStatsMetrics(stats), nil
+			}},
+			{"filesystem", func() (MetricFamilies, error) {
+				usage, err := c.GetFileSystemUsage()
+				if nil != err {
+					return nil, err
+				}
+				return _FileSystemUsageMetrics(usage), nil
+			}},
+			{"pressure", func() (MetricFamilies, error) {
+				stats, err := c.GetPressure()
+				if nil != err {
+					return nil, err
+				}
+				return _PressureMetrics(stats), nil
+			}},
+		}
+		families = make(MetricFamilies, 0)
+		success  = MetricFamily{
+			Name:    "scrape_collector_success",
+			Help:    "Whether a collector succeeded.",
+			Type:    MetricGauge,
+			Metrics: make([]Metric, 0, len(sources)),
+		}
+	)
+	for _, source := range sources {
+		value := 1.0
+		if gathered, err := source.gather(); nil != err {
+			value = 0
+		} else {
+			families = append(families, gathered...)
+		}
+		success.Metrics = append(success.Metrics, Metric{
+			Labels: []MetricLabel{{"collector", source.name}},
+			Value:  value,
+		})
+	}
+	families = append(families, success)
+	for i := range families {
+		families[i].Name = e.namespace + "_" + families[i].Name
+	}
+	return families
+}
+
+// Write renders a fresh Gather to w.
+func (e *PrometheusExporter) Write(w io.Writer) error {
+	return WritePrometheus(w, e.Gather())
+}
+
+// ServeHTTP serves OpenMetrics to scrapers that accept it and the
+// Prometheus text format otherwise.
+func (e *PrometheusExporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		buffer      bytes.Buffer
+		contentType = PrometheusContentType
+		write       = WritePrometheus
+	)
+	if strings.Contains(r.Header.Get("Accept"), "application/openmetrics-text") {
+		contentType = OpenMetricsContentType
+		write = WriteOpenMetrics
+	}
+	if err := write(&buffer, e.Gather()); nil != err {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	_, _ = w.Write(buffer.Bytes())
+}
+
+var (
+	_HelpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	_LabelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func _FormatMetricValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+func _WriteMetric(w *bufio.Writer, name string, metric Metric, timestamp string) {
+	_, _ = w.WriteString(name)
+	if len(metric.Labels) > 0 {
+		_ = w.WriteByte('{')
+		for i, label := range metric.Labels {
+			if i > 0 {
+				_ = w.WriteByte(',')
+			}
+			_, _ = w.WriteString(label.Name)
+			_, _ = w.WriteString(`="`)
+			_, _ = w.WriteString(_LabelEscaper.Replace(label.Value))
+			_ = w.WriteByte('"')
+		}
+		_ = w.WriteByte('}')
+	}
+	_ = w.WriteByte(' ')
+	_, _ = w.WriteString(_FormatMetricValue(metric.Value))
+	if len(timestamp) > 0 {
+		_ = w.WriteByte(' ')
+		_, _ = w.WriteString(timestamp)
+	}
+	_ = w.WriteByte('\n')
+}
+
+// WritePrometheus renders families in the Prometheus text exposition
+// format, version 0.0.4. Families without metrics are left out.
+func WritePrometheus(w io.Writer, families MetricFamilies) error {
+	writer := bufio.NewWriter(w)
+	for _, family := range families {
+		if len(family.Metrics) == 0 {
+			continue
+		}
+		_, _ = writer.WriteString("# HELP " + family.Name + " " + _HelpEscaper.Replace(family.Help) + "\n")
+		_, _ = writer.WriteString("# TYPE " + family.Name + " " + family.Type + "\n")
+		for _, metric := range family.Metrics {
+			_WriteMetric(writer, family.Name, metric, "")
+		}
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,327 @@
+package sysinfo_go
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// RecordTimeFormat names the directory of each recording. It has a fixed
+// width so recordings sort by time.
+const RecordTimeFormat = "20060102T150405.000000000Z"
+
+// RecordedProcFiles are the files under /proc copied by every recording.
+var RecordedProcFiles = []string{
+	UptimeFile,
+	MemInfoFile,
+	VMStatFile,
+	StatFile,
+	LoadAvgFile,
+	CPUInfoFile,
+	NetworkStatFile,
+	InterruptFile,
+	DiskStatFile,
+	MountInfoFile,
+	OSReleaseFile,
+	path.Join(PressureDirectory, PressureCPU),
+	path.Join(PressureDirectory, PressureMemory),
+	path.Join(PressureDirectory, PressureIO),
+	path.Join(PressureDirectory, PressureIRQ),
+	path.Join(ProcDirectory, "self", ProcessCgroupFile),
+}
+
+// RecordedProcessFiles and RecordedProcessLinks are copied from the /proc
+// directory of every process.
+var (
+	RecordedProcessFiles = []string{ProcessStatFile, ProcessStatusFile, ProcessCmdLineFile, ProcessCommFile, ProcessCgroupFile}
+	RecordedProcessLinks = []string{ProcessExeLink, ProcessCwdLink}
+)
+
+// RecordedSysFiles are the files under /sys copied by every recording. The
+// logical block size of every disk in /proc/diskstats is copied as well.
+var RecordedSysFiles = []string{
+	CPUOnlineFile,
+	HypervisorTypeFile,
+	DMISystemVendorFile,
+	DMIProductNameFile,
+	path.Join(CgroupDirectory, CgroupControllersFile),
+	path.Join(CgroupUnifiedDirectory, CgroupControllersFile),
+}
+
+// RecordedCgroupFiles are copied from the cgroup v2 group of every process
+// and its ancestors up to the root of the cgroup2 mount.
+var RecordedCgroupFiles = []string{
+	CgroupControllersFile,
+	CgroupCPUStatFile,
+	CgroupCPUMaxFile,
+	CgroupMemoryCurrentFile,
+	CgroupMemoryMaxFile,
+	CgroupMemoryStatFile,
+	CgroupMemoryEventsFile,
+	CgroupIOStatFile,
+	CgroupPidsCurrentFile,
+	CgroupPidsMaxFile,
+	CgroupCPUSetEffectiveFile,
+	PressureCPU + ".pressure",
+	PressureMemory + ".pressure",
+	PressureIO + ".pressure",
+	PressureIRQ + ".pressure",
+}
+
+// RecordedCgroupV1Files are copied from the v1 groups of the recording
+// process, whose limits GetEffectiveLimits reports on hybrid hosts.
+var RecordedCgroupV1Files = []string{
+	CgroupV1CPUAcctUsageFile,
+	CgroupV1CPUQuotaFile,
+	CgroupV1CPUPeriodFile,
+	CgroupV1MemoryUsageFile,
+	CgroupV1MemoryLimitFile,
+	CgroupV1MemoryStatFile,
+	CgroupV1BlkioServiceBytesFile,
+	CgroupV1BlkioServicedFile,
+	CgroupV1CPUSetEffectiveFile,
+}
+
+// RecordWriter stores the files of recordings. Names are slash separated
+// and relative, starting with the directory of the recording.
+type RecordWriter interface {
+	WriteFile(name string, data []byte, at time.Time) error
+	WriteLink(name, target string, at time.Time) error
+	Close() error
+}
+
+type _DirectoryRecordWriter struct {
+	root string
+}
+
+// NewDirectoryRecordWriter stores recordings below root, creating it when
+// needed.
+func NewDirectoryRecordWriter(root string) (RecordWriter, error) {
+	if err := os.MkdirAll(root, 0755); nil != err {
+		return nil, err
+	}
+	return &_DirectoryRecordWriter{root: root}, nil
+}
+
+func (w *_DirectoryRecordWriter) _Create(name string) (string, error) {
+	name = filepath.Join(w.root, filepath.FromSlash(name))
+	return name, os.MkdirAll(filepath.Dir(name), 0755)
+}
+
+func (w *_DirectoryRecordWriter) WriteFile(name string, data []byte, at time.Time) error {
+	name, err := w._Create(name)
+	if nil != err {
+		return err
+	}
+	if err := os.WriteFile(name, data, 0644); nil != err {
+		return err
+	}
+	return os.Chtimes(name, at, at)
+}
+
+func (w *_DirectoryRecordWriter) WriteLink(name, target string, at time.Time) error {
+	name, err := w._Create(name)
+	if nil != err {
+		return err
+	}
+	return os.Symlink(target, name)
+}
+
+func (w *_DirectoryRecordWriter) Close() error {
+	return nil
+}
+
+type _TarRecordWriter struct {
+	writer *tar.Writer
+}
+
+// NewTarRecordWriter streams recordings to w as a tar archive. Closing it
+// writes the end of the archive but does not close w.
+func NewTarRecordWriter(w io.Writer) RecordWriter {
+	return &_TarRecordWriter{writer: tar.NewWriter(w)}
+}
+
+func (w *_TarRecordWriter) WriteFile(name string, data []byte, at time.Time) error {
+	header := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     int64(len(data)),
+		Mode:     0644,
+		ModTime:  at,
+	}
+	if err := w.writer.WriteHeader(header); nil != err {
+		return err
+	}
+	_, err := w.writer.Write(data)
+	return err
+}
+
+func (w *_TarRecordWriter) WriteLink(name, target string, at time.Time) error {
+	return w.writer.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeSymlink,
+		Name:     name,
+		Linkname: target,
+		Mode:     0777,
+		ModTime:  at,
+	})
+}
+
+func (w *_TarRecordWriter) Close() error {
+	return w.writer.Close()
+}
+
+// Recorder copies the files the collectors read into a RecordWriter, so
+// that a Replay can later collect exactly what the host reported.
+type Recorder struct {
+	collector *Collector
+	writer    RecordWriter
+}
+
+func NewRecorder(collector *Collector, writer RecordWriter) *Recorder {
+	if nil == collector {
+		collector = DefaultCollector
+	}
+	return &Recorder{collector: collector, writer: writer}
+}
+
+// Record copies the files once, below a directory named after at. Files that
+// cannot be read are left out, so collecting from the recording fails the
+// way it would have on the host; only /proc/stat, which every process needs
+// for the boot time, is required.
+func (r *Recorder) Record(at time.Time) error {
+	var (
+		c      = r.collector
+		prefix = at.UTC().Format(RecordTimeFormat)
+		groups = make(map[string]bool)
+	)
+	copyFile := func(name string, read func(elem ...string) ([]byte, error)) error {
+		contents, err := read(name)
+		if nil != err {
+			return nil
+		}
+		return r.writer.WriteFile(path.Join(prefix, name), contents, at)
+	}
+	if contents, err := c._ReadProcFile(StatFile); nil != err {
+		return err
+	} else if err := r.writer.WriteFile(path.Join(prefix, StatFile), contents, at); nil != err {
+		return err
+	}
+	for _, name := range RecordedProcFiles {
+		if name == StatFile {
+			continue
+		}
+		if err := copyFile(name, c._ReadProcFile); nil != err {
+			return err
+		}
+	}
+	for _, name := range RecordedSysFiles {
+		if err := copyFile(name, c._ReadSysFile); nil != err {
+			return err
+		}
+	}
+	if stats, err := c.GetDiskStats(); nil == err {
+		for _, stat := range stats {
+			if err := copyFile(path.Join(BlockClassDirectory, stat.Device, LogicalBlockSizeFile), c._ReadSysFile); nil != err {
+				return err
+			}
+		}
+	}
+
+	pids, err := c.ListProcessId()
+	if nil != err {
+		return err
+	}
+	root, mountRoot := c._CgroupV2Root()
+	directories := []string{"self"}
+	for _, pid := range pids {
+		directories = append(directories, strconv.Itoa(pid))
+	}
+	for _, directory := range directories {
+		if directory != "self" {
+			for _, name := range RecordedProcessFiles {
+				if err := copyFile(path.Join(ProcDirectory, directory, name), c._ReadProcFile); nil != err {
+					return err
+				}
+			}
+			for _, name := range RecordedProcessLinks {
+				target, err := c._ReadLink(c._ProcPath(directory, name))
+				if nil != err {
+					continue
+				}
+				if err := r.writer.WriteLink(path.Join(prefix, ProcDirectory, directory, name), target, at); nil != err {
+					return err
+				}
+			}
+		}
+		if cgroups, err := c._GetProcessCgroups(directory); nil == err {
+			for _, cgroup := range cgroups {
+				if cgroup.HierarchyId != 0 {
+					continue
+				}
+				group, ok := _CgroupMountGroup(mountRoot, path.Clean("/"+cgroup.Path))
+				if !ok {
+					continue
+				}
+				for ; !groups[group]; group = path.Dir(group) {
+					groups[group] = true
+					if group == "/" {
+						break
+					}
+				}
+			}
+		}
+	}
+
+	for group := range groups {
+		for _, name := range RecordedCgroupFiles {
+			if err := copyFile(path.Join(root, group, name), c._ReadSysFile); nil != err {
+				return err
+			}
+		}
+	}
+	readMount := func(elem ...string) ([]byte, error) {
+		return fs.ReadFile(c._FS(), c._MountPath(elem[0]))
+	}
+	if mounts, err := c.GetMounts(); nil == err {
+		if cgroups, err := c._GetProcessCgroups("self"); nil == err {
+			for _, directory := range _CgroupV1Directories(mounts, cgroups) {
+				for _, name := range RecordedCgroupV1Files {
+					if err := copyFile(path.Join(directory, name), readMount); nil != err {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Run records every interval until ctx is done or count recordings were
+// made, count 0 meaning no limit.
+func (r *Recorder) Run(ctx context.Context, interval time.Duration, count int) error {
+	if interval <= 0 {
+		return errors.New("record interval must be positive")
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for n := 1; ; n++ {
+		if err := r.Record(time.Now()); nil != err {
+			return err
+		}
+		if count > 0 && n >= count {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
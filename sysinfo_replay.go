@@ -0,0 +1,288 @@
+package sysinfo_go
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// _ArchiveFile is a file, directory or symbolic link read from a tar
+// archive. It is its own fs.FileInfo and fs.DirEntry.
+type _ArchiveFile struct {
+	name     string
+	mode     fs.FileMode
+	modTime  time.Time
+	data     []byte
+	link     string
+	children []*_ArchiveFile
+}
+
+func (f *_ArchiveFile) Name() string               { return path.Base(f.name) }
+func (f *_ArchiveFile) Size() int64                { return int64(len(f.data)) }
+func (f *_ArchiveFile) Mode() fs.FileMode          { return f.mode }
+func (f *_ArchiveFile) ModTime() time.Time         { return f.modTime }
+func (f *_ArchiveFile) IsDir() bool                { return f.mode.IsDir() }
+func (f *_ArchiveFile) Sys() interface{}           { return nil }
+func (f *_ArchiveFile) Type() fs.FileMode          { return f.mode.Type() }
+func (f *_ArchiveFile) Info() (fs.FileInfo, error) { return f, nil }
+
+type _OpenArchiveFile struct {
+	*bytes.Reader
+	file   *_ArchiveFile
+	offset int
+}
+
+func (f *_OpenArchiveFile) Stat() (fs.FileInfo, error) {
+	return f.file, nil
+}
+
+func (f *_OpenArchiveFile) Close() error {
+	return nil
+}
+
+func (f *_OpenArchiveFile) ReadDir(count int) ([]fs.DirEntry, error) {
+	if !f.file.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: f.file.name, Err: errors.New("not a directory")}
+	}
+	remaining := f.file.children[f.offset:]
+	if count > 0 && len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	if count > 0 && count < len(remaining) {
+		remaining = remaining[:count]
+	}
+	f.offset += len(remaining)
+	entries := make([]fs.DirEntry, 0, len(remaining))
+	for _, child := range remaining {
+		entries = append(entries, child)
+	}
+	return entries, nil
+}
+
+// _ArchiveFS holds a whole tar archive in memory. Directories missing from
+// the archive are implied by the files below them, and symbolic links are
+// only resolved by ReadLink.
+type _ArchiveFS map[string]*_ArchiveFile
+
+func (a _ArchiveFS) _Add(file *_ArchiveFile) {
+	if existing, ok := a[file.name]; ok {
+		if existing.IsDir() && file.IsDir() {
+			existing.modTime = file.modTime
+			return
+		}
+		file.children = existing.children
+	}
+	a[file.name] = file
+	if file.name == "." {
+		return
+	}
+	parent := path.Dir(file.name)
+	if _, ok := a[parent]; !ok {
+		a._Add(&_ArchiveFile{name: parent, mode: fs.ModeDir | 0755, modTime: file.modTime})
+	}
+	siblings := a[parent].children
+	for i, sibling := range siblings {
+		if sibling.name == file.name {
+			siblings[i] = file
+			return
+		}
+	}
+	a[parent].children = append(siblings, file)
+}
+
+func (a _ArchiveFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	file, ok := a[name]
+	if !ok || file.mode&fs.ModeSymlink != 0 {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return &_OpenArchiveFile{Reader: bytes.NewReader(file.data), file: file}, nil
+}
+
+func (a _ArchiveFS) ReadLink(name string) (string, error) {
+	file, ok := a[name]
+	if !ok {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrNotExist}
+	}
+	if file.mode&fs.ModeSymlink == 0 {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+	}
+	return file.link, nil
+}
+
+// ReadTar reads a tar archive, such as one written by a tar RecordWriter,
+// into memory. The returned fs.FS also resolves symbolic links through a
+// ReadLink method, which collectors use for /proc/[pid]/exe.
+func ReadTar(r io.Reader) (fs.FS, error) {
+	var (
+		archive = make(_ArchiveFS)
+		reader  = tar.NewReader(r)
+	)
+	archive._Add(&_ArchiveFile{name: ".", mode: fs.ModeDir | 0755})
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if nil != err {
+			return nil, err
+		}
+		name := path.Clean(strings.TrimPrefix(header.Name, "/"))
+		if !fs.ValidPath(name) {
+			return nil, errors.New("invalid name in archive: " + header.Name)
+		}
+		file := &_ArchiveFile{name: name, modTime: header.ModTime}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			file.mode = fs.ModeDir | fs.FileMode(header.Mode).Perm()
+		case tar.TypeSymlink:
+			file.mode = fs.ModeSymlink | 0777
+			file.link = header.Linkname
+		case tar.TypeReg:
+			file.mode = fs.FileMode(header.Mode).Perm()
+			if file.data, err = io.ReadAll(reader); nil != err {
+				return nil, err
+			}
+		default:
+			continue
+		}
+		archive._Add(file)
+	}
+	for _, file := range archive {
+		sort.Slice(file.children, func(i, j int) bool {
+			return file.children[i].name < file.children[j].name
+		})
+	}
+	return archive, nil
+}
+
+// _DirectoryFS reads a directory of the host like os.DirFS and resolves
+// symbolic links inside it.
+type _DirectoryFS struct {
+	fs.FS
+	root string
+}
+
+func (d *_DirectoryFS) ReadLink(name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+	}
+	return os.Readlink(filepath.Join(d.root, filepath.FromSlash(name)))
+}
+
+// _SubFS is fs.Sub keeping the ReadLink method of the parent.
+type _SubFS struct {
+	parent fs.FS
+	prefix string
+}
+
+func (s *_SubFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	return s.parent.Open(path.Join(s.prefix, name))
+}
+
+func (s *_SubFS) ReadLink(name string) (string, error) {
+	fsys, ok := s.parent.(interface {
+		ReadLink(name string) (string, error)
+	})
+	if !ok {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: errors.New("readlink not supported")}
+	}
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+	}
+	return fsys.ReadLink(path.Join(s.prefix, name))
+}
+
+// Replay reads the recordings made by a Recorder, oldest first.
+type Replay struct {
+	fsys  fs.FS
+	names []string
+	times []time.Time
+}
+
+// NewReplay finds the recordings at the top of fsys.
+func NewReplay(fsys fs.FS) (*Replay, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if nil != err {
+		return nil, err
+	}
+	replay := &Replay{fsys: fsys}
+	for _, entry := range entries {
+		at, err := time.Parse(RecordTimeFormat, entry.Name())
+		if !entry.IsDir() || nil != err {
+			continue
+		}
+		replay.names = append(replay.names, entry.Name())
+		replay.times = append(replay.times, at)
+	}
+	if len(replay.names) == 0 {
+		return nil, errors.New("no recordings found")
+	}
+	return replay, nil
+}
+
+// OpenReplay opens a directory or a tar archive, gzip compressed when the
+// name ends in .gz, written by a Recorder.
+func OpenReplay(name string) (*Replay, error) {
+	info, err := os.Stat(name)
+	if nil != err {
+		return nil, err
+	}
+	if info.IsDir() {
+		return NewReplay(&_DirectoryFS{FS: os.DirFS(name), root: name})
+	}
+	file, err := os.Open(name)
+	if nil != err {
+		return nil, err
+	}
+	defer file.Close()
+	var reader io.Reader = file
+	if strings.HasSuffix(name, ".gz") {
+		decompressor, err := gzip.NewReader(file)
+		if nil != err {
+			return nil, err
+		}
+		defer decompressor.Close()
+		reader = decompressor
+	}
+	fsys, err := ReadTar(reader)
+	if nil != err {
+		return nil, err
+	}
+	return NewReplay(fsys)
+}
+
+// Len returns the number of recordings.
+func (r *Replay) Len() int {
+	return len(r.names)
+}
+
+// Time returns when recording i was made.
+func (r *Replay) Time(i int) time.Time {
+	return r.times[i]
+}
+
+// FS returns recording i, holding proc and sys at its top.
+func (r *Replay) FS(i int) fs.FS {
+	return &_SubFS{parent: r.fsys, prefix: r.names[i]}
+}
+
+// Collector returns a collector reading recording i. Rate samplers given
+// Time(i) reproduce the rates seen on the host.
+func (r *Replay) Collector(i int) *Collector {
+	return NewCollector(r.FS(i), ProcDirectory, SysDirectory)
+}
@@ -0,0 +1,175 @@
+package sysinfo_go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	SnapshotUName             = "uname"
+	SnapshotSystemInformation = "systemInformation"
+	SnapshotStat              = "stat"
+	SnapshotMemInfo           = "memInfo"
+	SnapshotLoad              = "load"
+	SnapshotUptime            = "uptime"
+	SnapshotCPUInformation    = "cpuInformation"
+	SnapshotNetworkStats      = "networkStats"
+	SnapshotNetworkInterfaces = "networkInterfaces"
+	SnapshotDiskStats         = "diskStats"
+	SnapshotMounts            = "mounts"
+)
+
+const (
+	DefaultSnapshotTimeout = 5 * time.Second
+)
+
+// _SnapshotSources collect one part of a snapshot each. They return the
+// assignment instead of setting the field themselves, so sources still
+// running after the deadline never touch the returned snapshot. UName,
+// NetworkInterfaces and most of SystemInformation come from system calls and
+// always describe the running host.
+var _SnapshotSources = map[string]func(c *Collector) (func(s *Snapshot), error){
+	SnapshotUName: func(c *Collector) (func(s *Snapshot), error) {
+		uname, err := GetUName()
+		return func(s *Snapshot) { s.UName = uname }, err
+	},
+	SnapshotSystemInformation: func(c *Collector) (func(s *Snapshot), error) {
+		info, err := c.GetSystemInformation()
+		return func(s *Snapshot) { s.SystemInformation = info }, err
+	},
+	SnapshotStat: func(c *Collector) (func(s *Snapshot), error) {
+		stat, err := c.GetStat()
+		return func(s *Snapshot) { s.Stat = stat }, err
+	},
+	SnapshotMemInfo: func(c *Collector) (func(s *Snapshot), error) {
+		mem, err := c.GetMemInfo()
+		return func(s *Snapshot) { s.MemInfo = mem }, err
+	},
+	SnapshotLoad: func(c *Collector) (func(s *Snapshot), error) {
+		load, err := c.GetLoadAvg()
+		return func(s *Snapshot) { s.Load = load }, err
+	},
+	SnapshotUptime: func(c *Collector) (func(s *Snapshot), error) {
+		uptime, err := c.GetUptime()
+		return func(s *Snapshot) { s.Uptime = uptime }, err
+	},
+	SnapshotCPUInformation: func(c *Collector) (func(s *Snapshot), error) {
+		info, err := c.GetCPUInfo()
+		return func(s *Snapshot) { s.CPUInformation = info }, err
+	},
+	SnapshotNetworkStats: func(c *Collector) (func(s *Snapshot), error) {
+		stats, err := c.GetNetworkStats()
+		return func(s *Snapshot) { s.NetworkStats = stats }, err
+	},
+	SnapshotNetworkInterfaces: func(c *Collector) (func(s *Snapshot), error) {
+		interfaces, err := GetNetworkInterface()
+		return func(s *Snapshot) { s.NetworkInterfaces = interfaces }, err
+	},
+	SnapshotDiskStats: func(c *Collector) (func(s *Snapshot), error) {
+		stats, err := c.GetDiskStats()
+		return func(s *Snapshot) { s.DiskStats = stats }, err
+	},
+	SnapshotMounts: func(c *Collector) (func(s *Snapshot), error) {
+		mounts, err := c.GetMounts()
+		return func(s *Snapshot) { s.Mounts = mounts }, err
+	},
+}
+
+// SnapshotOptions controls GetSnapshot. Context defaults to the background
+// context and Timeout, when positive, shortens its deadline further. Sources
+// selects the Snapshot* parts to collect, all of them when empty.
+type SnapshotOptions struct {
+	Context context.Context
+	Timeout time.Duration
+	Sources []string
+}
+
+func NewSnapshotOptions() *SnapshotOptions {
+	return &SnapshotOptions{
+		Context: context.Background(),
+		Timeout: DefaultSnapshotTimeout,
+	}
+}
+
+func GetSnapshot(options *SnapshotOptions) (*Snapshot, error) {
+	return DefaultCollector.GetSnapshot(options)
+}
+
+// GetSnapshot collects the sources in parallel. A source failing or missing
+// the deadline is reported in Errors and leaves its field empty; an error is
+// only returned when no source could be collected at all.
+func (c *Collector) GetSnapshot(options *SnapshotOptions) (*Snapshot, error) {
+	if nil == options {
+		options = NewSnapshotOptions()
+	}
+	ctx := options.Context
+	if nil == ctx {
+		ctx = context.Background()
+	}
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+	if err := ctx.Err(); nil != err {
+		return nil, err
+	}
+	names := options.Sources
+	if len(names) == 0 {
+		names = make([]string, 0, len(_SnapshotSources))
+		for name := range _SnapshotSources {
+			names = append(names, name)
+		}
+	}
+	type result struct {
+		name   string
+		assign func(s *Snapshot)
+		err    error
+	}
+	var (
+		snapshot = &Snapshot{Timestamp: time.Now(), Errors: make(map[string]string)}
+		results  = make(chan result, len(names))
+		pending  = make(map[string]bool, len(names))
+		launched = 0
+	)
+	for _, name := range names {
+		source, ok := _SnapshotSources[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown snapshot source %q", name)
+		}
+		if pending[name] {
+			continue
+		}
+		pending[name] = true
+		launched++
+		go func(name string, source func(c *Collector) (func(s *Snapshot), error)) {
+			assign, err := source(c)
+			results <- result{name, assign, err}
+		}(name, source)
+	}
+	for len(pending) > 0 {
+		select {
+		case result := <-results:
+			delete(pending, result.name)
+			if nil != result.err {
+				snapshot.Errors[result.name] = result.err.Error()
+				continue
+			}
+			result.assign(snapshot)
+		case <-ctx.Done():
+			for name := range pending {
+				snapshot.Errors[name] = ctx.Err().Error()
+				delete(pending, name)
+			}
+		}
+	}
+	if len(snapshot.Errors) == launched {
+		return nil, errors.New("every source of the snapshot failed")
+	}
+	if len(snapshot.Errors) == 0 {
+		snapshot.Errors = nil
+	}
+	return snapshot, nil
+}
@@ -1,5 +1,9 @@
 package sysinfo_go
 
+import (
+	"time"
+)
+
 type NetworkInterface struct {
 	Name            string   `json:"name"`
 	Addresses       []string `json:"addresses"`
@@ -9,33 +13,42 @@ type NetworkInterface struct {
 type NetworkInterfaces []NetworkInterface
 
 type Load struct {
-	Load1  float64 `json:"load1"`
-	Load5  float64 `json:"load5"`
-	Load15 float64 `json:"load15"`
+	Load1    float64 `json:"load1"`
+	Load5    float64 `json:"load5"`
+	Load15   float64 `json:"load15"`
+	Runnable int64   `json:"runnable"`
+	Entities int64   `json:"entities"`
 }
 
 type SystemInformation struct {
-	Uptime        string `json:"uptime"`
-	TotalRam      uint64 `json:"totalRam"`
-	AvailableRam  uint64 `json:"availableRam"`
-	TotalSwap     uint64 `json:"totalSwap"`
-	AvailableSwap uint64 `json:"availableSwap"`
-	Processes     uint64 `json:"processes"`
-	Loads         *Load  `json:"load"`
+	Uptime        time.Duration `json:"uptime"`
+	BootTime      time.Time     `json:"bootTime"`
+	TotalRam      uint64        `json:"totalRam"`
+	AvailableRam  uint64        `json:"availableRam"`
+	FreeRam       uint64        `json:"freeRam"`
+	SharedRam     uint64        `json:"sharedRam"`
+	BufferRam     uint64        `json:"bufferRam"`
+	TotalSwap     uint64        `json:"totalSwap"`
+	AvailableSwap uint64        `json:"availableSwap"`
+	TotalHighRam  uint64        `json:"totalHighRam"`
+	FreeHighRam   uint64        `json:"freeHighRam"`
+	Processes     uint64        `json:"processes"`
+	Loads         *Load         `json:"load"`
 }
 
 type ProcessorInformation struct {
-	Id             int64  `json:"id"`
-	CoreId         int64  `json:"coreId"`
-	PhysicalId     int64  `json:"physicalId"`
-	VendorId       string `json:"vendorId"`
-	CPUFamily      string `json:"cpuFamily"`
-	ModelId        string `json:"modelId"`
-	ModelName      string `json:"modelName"`
-	CPUFrequency   string `json:"cpuFrequency"`
-	CPUCores       string `json:"cpuCores"`
-	CacheSize      string `json:"cacheSize"`
-	CacheAlignment string `json:"cacheAlignment"`
+	Id             int64    `json:"id"`
+	CoreId         int64    `json:"coreId"`
+	PhysicalId     int64    `json:"physicalId"`
+	VendorId       string   `json:"vendorId"`
+	CPUFamily      string   `json:"cpuFamily"`
+	ModelId        string   `json:"modelId"`
+	ModelName      string   `json:"modelName"`
+	CPUFrequency   string   `json:"cpuFrequency"`
+	CPUCores       string   `json:"cpuCores"`
+	CacheSize      string   `json:"cacheSize"`
+	CacheAlignment string   `json:"cacheAlignment"`
+	Flags          []string `json:"flags"`
 }
 
 type CPUInformation struct {
@@ -58,22 +71,97 @@ type CPUStat struct {
 }
 
 type Stat struct {
-	CPUStats         []CPUStat `json:"cpuStats"`
-	BootTime         int64     `json:"bootTime"`
-	Processes        int64     `json:"processes"`
-	ProcessesRunning int64     `json:"processesRunning"`
-	ProcessesBlocked int64     `json:"processesBlocked"`
+	CPUStats         []CPUStat   `json:"cpuStats"`
+	BootTime         int64       `json:"bootTime"`
+	Processes        int64       `json:"processes"`
+	ProcessesRunning int64       `json:"processesRunning"`
+	ProcessesBlocked int64       `json:"processesBlocked"`
+	Interrupts       int64       `json:"interrupts"`
+	InterruptCounts  []int64     `json:"interruptCounts"`
+	ContextSwitches  int64       `json:"contextSwitches"`
+	SoftIRQs         SoftIRQStat `json:"softIrqs"`
+}
+
+type SoftIRQStat struct {
+	Total   int64 `json:"total"`
+	HI      int64 `json:"hi"`
+	Timer   int64 `json:"timer"`
+	NetTx   int64 `json:"netTx"`
+	NetRx   int64 `json:"netRx"`
+	Block   int64 `json:"block"`
+	IRQPoll int64 `json:"irqPoll"`
+	Tasklet int64 `json:"tasklet"`
+	Sched   int64 `json:"sched"`
+	HRTimer int64 `json:"hrtimer"`
+	RCU     int64 `json:"rcu"`
 }
 
 type MemInfo struct {
-	Total      int64 `json:"total"`
-	Free       int64 `json:"free"`
-	Available  int64 `json:"available"`
-	Buffered   int64 `json:"buffered"`
-	Cached     int64 `json:"cached"`
-	SwapCached int64 `json:"swapCached"`
-	SwapTotal  int64 `json:"swapTotal"`
-	SwapFree   int64 `json:"swapFree"`
+	Total               int64            `json:"total"`
+	Free                int64            `json:"free"`
+	Available           int64            `json:"available"`
+	Buffered            int64            `json:"buffered"`
+	Cached              int64            `json:"cached"`
+	SwapCached          int64            `json:"swapCached"`
+	Active              int64            `json:"active"`
+	Inactive            int64            `json:"inactive"`
+	ActiveAnon          int64            `json:"activeAnon"`
+	InactiveAnon        int64            `json:"inactiveAnon"`
+	ActiveFile          int64            `json:"activeFile"`
+	InactiveFile        int64            `json:"inactiveFile"`
+	Unevictable         int64            `json:"unevictable"`
+	Mlocked             int64            `json:"mlocked"`
+	HighTotal           int64            `json:"highTotal"`
+	HighFree            int64            `json:"highFree"`
+	LowTotal            int64            `json:"lowTotal"`
+	LowFree             int64            `json:"lowFree"`
+	MmapCopy            int64            `json:"mmapCopy"`
+	SwapTotal           int64            `json:"swapTotal"`
+	SwapFree            int64            `json:"swapFree"`
+	Zswap               int64            `json:"zswap"`
+	Zswapped            int64            `json:"zswapped"`
+	Dirty               int64            `json:"dirty"`
+	Writeback           int64            `json:"writeback"`
+	AnonPages           int64            `json:"anonPages"`
+	Mapped              int64            `json:"mapped"`
+	Shmem               int64            `json:"shmem"`
+	KernelReclaimable   int64            `json:"kernelReclaimable"`
+	Slab                int64            `json:"slab"`
+	SlabReclaimable     int64            `json:"slabReclaimable"`
+	SlabUnreclaimable   int64            `json:"slabUnreclaimable"`
+	KernelStack         int64            `json:"kernelStack"`
+	PageTables          int64            `json:"pageTables"`
+	SecondaryPageTables int64            `json:"secondaryPageTables"`
+	NFSUnstable         int64            `json:"nfsUnstable"`
+	Bounce              int64            `json:"bounce"`
+	WritebackTmp        int64            `json:"writebackTmp"`
+	CommitLimit         int64            `json:"commitLimit"`
+	CommittedAS         int64            `json:"committedAS"`
+	VmallocTotal        int64            `json:"vmallocTotal"`
+	VmallocUsed         int64            `json:"vmallocUsed"`
+	VmallocChunk        int64            `json:"vmallocChunk"`
+	Percpu              int64            `json:"percpu"`
+	HardwareCorrupted   int64            `json:"hardwareCorrupted"`
+	AnonHugePages       int64            `json:"anonHugePages"`
+	ShmemHugePages      int64            `json:"shmemHugePages"`
+	ShmemPmdMapped      int64            `json:"shmemPmdMapped"`
+	FileHugePages       int64            `json:"fileHugePages"`
+	FilePmdMapped       int64            `json:"filePmdMapped"`
+	CmaTotal            int64            `json:"cmaTotal"`
+	CmaFree             int64            `json:"cmaFree"`
+	Unaccepted          int64            `json:"unaccepted"`
+	Balloon             int64            `json:"balloon"`
+	HugePagesTotal      int64            `json:"hugePagesTotal"`
+	HugePagesFree       int64            `json:"hugePagesFree"`
+	HugePagesReserved   int64            `json:"hugePagesReserved"`
+	HugePagesSurplus    int64            `json:"hugePagesSurplus"`
+	HugePageSize        int64            `json:"hugePageSize"`
+	Hugetlb             int64            `json:"hugetlb"`
+	DirectMap4k         int64            `json:"directMap4k"`
+	DirectMap2M         int64            `json:"directMap2M"`
+	DirectMap4M         int64            `json:"directMap4M"`
+	DirectMap1G         int64            `json:"directMap1G"`
+	Others              map[string]int64 `json:"others"`
 }
 
 type Uptime struct {
@@ -82,14 +170,72 @@ type Uptime struct {
 }
 
 type VMStat struct {
+	NrFreePages                int64            `json:"nrFreePages"`
+	NrInactiveAnon             int64            `json:"nrInactiveAnon"`
+	NrActiveAnon               int64            `json:"nrActiveAnon"`
+	NrInactiveFile             int64            `json:"nrInactiveFile"`
+	NrActiveFile               int64            `json:"nrActiveFile"`
+	NrAnonPages                int64            `json:"nrAnonPages"`
+	NrMapped                   int64            `json:"nrMapped"`
+	NrFilePages                int64            `json:"nrFilePages"`
+	NrDirty                    int64            `json:"nrDirty"`
+	NrWriteback                int64            `json:"nrWriteback"`
+	NrShmem                    int64            `json:"nrShmem"`
+	NrSlabReclaimable          int64            `json:"nrSlabReclaimable"`
+	NrSlabUnreclaimable        int64            `json:"nrSlabUnreclaimable"`
+	NrAnonTransparentHugePages int64            `json:"nrAnonTransparentHugePages"`
+	NrDirtied                  int64            `json:"nrDirtied"`
+	NrWritten                  int64            `json:"nrWritten"`
+	PageIn                     int64            `json:"pageIn"`
+	PageOut                    int64            `json:"pageOut"`
+	SwapIn                     int64            `json:"swapIn"`
+	SwapOut                    int64            `json:"swapOut"`
+	PageFree                   int64            `json:"pageFree"`
+	PageActivate               int64            `json:"pageActivate"`
+	PageDeactivate             int64            `json:"pageDeactivate"`
+	PageFault                  int64            `json:"pageFault"`
+	PageMajorFault             int64            `json:"pageMajorFault"`
+	PageRefill                 int64            `json:"pageRefill"`
+	PageStealKswapd            int64            `json:"pageStealKswapd"`
+	PageStealDirect            int64            `json:"pageStealDirect"`
+	PageScanKswapd             int64            `json:"pageScanKswapd"`
+	PageScanDirect             int64            `json:"pageScanDirect"`
+	SlabsScanned               int64            `json:"slabsScanned"`
+	KswapdInodeSteal           int64            `json:"kswapdInodeSteal"`
+	PageInodeSteal             int64            `json:"pageInodeSteal"`
+	OOMKill                    int64            `json:"oomKill"`
+	CompactStall               int64            `json:"compactStall"`
+	CompactFail                int64            `json:"compactFail"`
+	CompactSuccess             int64            `json:"compactSuccess"`
+	THPFaultAlloc              int64            `json:"thpFaultAlloc"`
+	THPFaultFallback           int64            `json:"thpFaultFallback"`
+	THPCollapseAlloc           int64            `json:"thpCollapseAlloc"`
+	THPCollapseAllocFailed     int64            `json:"thpCollapseAllocFailed"`
+	THPSplitPage               int64            `json:"thpSplitPage"`
+	THPSplitPageFailed         int64            `json:"thpSplitPageFailed"`
+	THPZeroPageAlloc           int64            `json:"thpZeroPageAlloc"`
+	THPSwpout                  int64            `json:"thpSwpout"`
+	Others                     map[string]int64 `json:"others"`
 }
 
 type NetworkStat struct {
-	Interface          string `json:"interface"`
-	ReceivedBytes      int64  `json:"receivedBytes"`
-	ReceivedPackets    int64  `json:"receivedPackets"`
-	TransmittedBytes   int64  `json:"transmittedBytes"`
-	TransmittedPackets int64  `json:"transmittedPackets"`
+	Interface             string `json:"interface"`
+	ReceivedBytes         int64  `json:"receivedBytes"`
+	ReceivedPackets       int64  `json:"receivedPackets"`
+	ReceivedErrors        int64  `json:"receivedErrors"`
+	ReceivedDropped       int64  `json:"receivedDropped"`
+	ReceivedFIFO          int64  `json:"receivedFifo"`
+	ReceivedFrame         int64  `json:"receivedFrame"`
+	ReceivedCompressed    int64  `json:"receivedCompressed"`
+	ReceivedMulticast     int64  `json:"receivedMulticast"`
+	TransmittedBytes      int64  `json:"transmittedBytes"`
+	TransmittedPackets    int64  `json:"transmittedPackets"`
+	TransmittedErrors     int64  `json:"transmittedErrors"`
+	TransmittedDropped    int64  `json:"transmittedDropped"`
+	TransmittedFIFO       int64  `json:"transmittedFifo"`
+	TransmittedCollisions int64  `json:"transmittedCollisions"`
+	TransmittedCarrier    int64  `json:"transmittedCarrier"`
+	TransmittedCompressed int64  `json:"transmittedCompressed"`
 }
 
 type NetworkStats []NetworkStat
@@ -127,8 +273,311 @@ type DiskStat struct {
 type DiskStats []DiskStat
 
 type FileSystemStat struct {
-	Available int64 `json:"available"`
-	Free      int64 `json:"free"`
-	Capacity  int64 `json:"capacity"`
-	Files     int64 `json:"files"`
+	Type             string   `json:"type"`
+	Magic            int64    `json:"magic"`
+	BlockSize        int64    `json:"blockSize"`
+	Available        int64    `json:"available"`
+	Free             int64    `json:"free"`
+	Used             int64    `json:"used"`
+	Capacity         int64    `json:"capacity"`
+	UsedPercent      float64  `json:"usedPercent"`
+	Files            int64    `json:"files"`
+	FreeFiles        int64    `json:"freeFiles"`
+	UsedFiles        int64    `json:"usedFiles"`
+	UsedFilesPercent float64  `json:"usedFilesPercent"`
+	NameLength       int64    `json:"nameLength"`
+	Flags            int64    `json:"flags"`
+	MountFlags       []string `json:"mountFlags"`
+	ReadOnly         bool     `json:"readOnly"`
+}
+
+type Process struct {
+	Pid              int       `json:"pid"`
+	ParentPid        int       `json:"parentPid"`
+	Name             string    `json:"name"`
+	CommandLine      []string  `json:"commandLine"`
+	Executable       string    `json:"executable"`
+	WorkingDirectory string    `json:"workingDirectory"`
+	State            string    `json:"state"`
+	Uid              int64     `json:"uid"`
+	EffectiveUid     int64     `json:"effectiveUid"`
+	Gid              int64     `json:"gid"`
+	EffectiveGid     int64     `json:"effectiveGid"`
+	Threads          int64     `json:"threads"`
+	ResidentSize     int64     `json:"residentSize"`
+	VirtualSize      int64     `json:"virtualSize"`
+	MinorFaults      int64     `json:"minorFaults"`
+	MajorFaults      int64     `json:"majorFaults"`
+	UserTime         float64   `json:"userTime"`
+	SystemTime       float64   `json:"systemTime"`
+	StartTime        time.Time `json:"startTime"`
+	Nice             int64     `json:"nice"`
+	Priority         int64     `json:"priority"`
+}
+
+type Processes []Process
+
+type CPUUtilisation struct {
+	CPUId     string  `json:"cpuId"`
+	User      float64 `json:"user"`
+	Nice      float64 `json:"nice"`
+	System    float64 `json:"system"`
+	Idle      float64 `json:"idle"`
+	IOWait    float64 `json:"iowait"`
+	IRQ       float64 `json:"irq"`
+	SoftIRQ   float64 `json:"softirq"`
+	Steal     float64 `json:"steal"`
+	Guest     float64 `json:"guest"`
+	GuestNice float64 `json:"guestNice"`
+	Usage     float64 `json:"usage"`
+}
+
+type CPUUsage struct {
+	Interval time.Duration    `json:"interval"`
+	Total    CPUUtilisation   `json:"total"`
+	CPUs     []CPUUtilisation `json:"cpus"`
+}
+
+type Interrupt struct {
+	IRQ         string   `json:"irq"`
+	Counts      []int64  `json:"counts"`
+	Total       int64    `json:"total"`
+	Chip        string   `json:"chip"`
+	HardwareIRQ string   `json:"hardwareIrq"`
+	Trigger     string   `json:"trigger"`
+	Devices     []string `json:"devices"`
+	Description string   `json:"description"`
+}
+
+type InterruptStats struct {
+	CPUs       []string    `json:"cpus"`
+	Interrupts []Interrupt `json:"interrupts"`
+}
+
+type InterruptRate struct {
+	IRQ     string    `json:"irq"`
+	Devices []string  `json:"devices"`
+	Rates   []float64 `json:"rates"`
+	Total   float64   `json:"total"`
+}
+
+type NetworkRate struct {
+	Interface          string  `json:"interface"`
+	ReceivedBytes      float64 `json:"receivedBytes"`
+	ReceivedPackets    float64 `json:"receivedPackets"`
+	ReceivedErrors     float64 `json:"receivedErrors"`
+	ReceivedDropped    float64 `json:"receivedDropped"`
+	TransmittedBytes   float64 `json:"transmittedBytes"`
+	TransmittedPackets float64 `json:"transmittedPackets"`
+	TransmittedErrors  float64 `json:"transmittedErrors"`
+	TransmittedDropped float64 `json:"transmittedDropped"`
+}
+
+type NetworkRates []NetworkRate
+
+type DiskRate struct {
+	Major             int64   `json:"major"`
+	Minor             int64   `json:"minor"`
+	Device            string  `json:"device"`
+	ReadsPerSecond    float64 `json:"readsPerSecond"`
+	WritesPerSecond   float64 `json:"writesPerSecond"`
+	DiscardsPerSecond float64 `json:"discardsPerSecond"`
+	ReadMerges        float64 `json:"readMerges"`
+	WriteMerges       float64 `json:"writeMerges"`
+	DiscardMerges     float64 `json:"discardMerges"`
+	ReadBytes         float64 `json:"readBytes"`
+	WriteBytes        float64 `json:"writeBytes"`
+	DiscardBytes      float64 `json:"discardBytes"`
+	ReadAwait         float64 `json:"readAwait"`
+	WriteAwait        float64 `json:"writeAwait"`
+	DiscardAwait      float64 `json:"discardAwait"`
+	Await             float64 `json:"await"`
+	ServiceTime       float64 `json:"serviceTime"`
+	AverageQueueSize  float64 `json:"averageQueueSize"`
+	Utilisation       float64 `json:"utilisation"`
+	InProgress        int64   `json:"inProgress"`
+	LogicalSectorSize int64   `json:"logicalSectorSize"`
+}
+
+type DiskRates []DiskRate
+
+type Mount struct {
+	MountId        int64    `json:"mountId"`
+	ParentId       int64    `json:"parentId"`
+	Major          int64    `json:"major"`
+	Minor          int64    `json:"minor"`
+	Root           string   `json:"root"`
+	MountPoint     string   `json:"mountPoint"`
+	Options        []string `json:"options"`
+	OptionalFields []string `json:"optionalFields"`
+	FileSystemType string   `json:"fileSystemType"`
+	Source         string   `json:"source"`
+	SuperOptions   []string `json:"superOptions"`
+}
+
+type Mounts []Mount
+
+type FileSystemUsage struct {
+	Mount Mount          `json:"mount"`
+	Stat  FileSystemStat `json:"stat"`
+}
+
+type FileSystemUsages []FileSystemUsage
+
+type PressureStall struct {
+	Avg10  float64 `json:"avg10"`
+	Avg60  float64 `json:"avg60"`
+	Avg300 float64 `json:"avg300"`
+	Total  int64   `json:"total"`
+}
+
+type Pressure struct {
+	Some *PressureStall `json:"some"`
+	Full *PressureStall `json:"full"`
+}
+
+type PressureStats struct {
+	CPU    *Pressure `json:"cpu"`
+	Memory *Pressure `json:"memory"`
+	IO     *Pressure `json:"io"`
+	IRQ    *Pressure `json:"irq"`
+}
+
+type ProcessCgroup struct {
+	HierarchyId int64    `json:"hierarchyId"`
+	Controllers []string `json:"controllers"`
+	Path        string   `json:"path"`
+}
+
+type ProcessCgroups []ProcessCgroup
+
+type CgroupCPUStat struct {
+	Usage         int64 `json:"usage"`
+	User          int64 `json:"user"`
+	System        int64 `json:"system"`
+	Periods       int64 `json:"periods"`
+	Throttled     int64 `json:"throttled"`
+	ThrottledTime int64 `json:"throttledTime"`
+}
+
+type CgroupCPUMax struct {
+	Quota  int64 `json:"quota"`
+	Period int64 `json:"period"`
+}
+
+type CgroupMemoryEvents struct {
+	Low          int64 `json:"low"`
+	High         int64 `json:"high"`
+	Max          int64 `json:"max"`
+	OOM          int64 `json:"oom"`
+	OOMKill      int64 `json:"oomKill"`
+	OOMGroupKill int64 `json:"oomGroupKill"`
+}
+
+type CgroupIOStat struct {
+	Major        int64 `json:"major"`
+	Minor        int64 `json:"minor"`
+	ReadBytes    int64 `json:"readBytes"`
+	WriteBytes   int64 `json:"writeBytes"`
+	ReadIOs      int64 `json:"readIos"`
+	WriteIOs     int64 `json:"writeIos"`
+	DiscardBytes int64 `json:"discardBytes"`
+	DiscardIOs   int64 `json:"discardIos"`
+}
+
+type Cgroup struct {
+	Path          string              `json:"path"`
+	Controllers   []string            `json:"controllers"`
+	CPUStat       *CgroupCPUStat      `json:"cpuStat"`
+	CPUMax        *CgroupCPUMax       `json:"cpuMax"`
+	MemoryCurrent int64               `json:"memoryCurrent"`
+	MemoryMax     int64               `json:"memoryMax"`
+	MemoryStat    map[string]int64    `json:"memoryStat"`
+	MemoryEvents  *CgroupMemoryEvents `json:"memoryEvents"`
+	IOStats       []CgroupIOStat      `json:"ioStats"`
+	PidsCurrent   int64               `json:"pidsCurrent"`
+	PidsMax       int64               `json:"pidsMax"`
+}
+
+type CgroupV1BlkioStat struct {
+	Major   int64 `json:"major"`
+	Minor   int64 `json:"minor"`
+	Read    int64 `json:"read"`
+	Write   int64 `json:"write"`
+	Sync    int64 `json:"sync"`
+	Async   int64 `json:"async"`
+	Discard int64 `json:"discard"`
+	Total   int64 `json:"total"`
+}
+
+type CgroupV1 struct {
+	Directories  map[string]string   `json:"directories"`
+	CPUUsage     int64               `json:"cpuUsage"`
+	CPUQuota     int64               `json:"cpuQuota"`
+	CPUPeriod    int64               `json:"cpuPeriod"`
+	MemoryUsage  int64               `json:"memoryUsage"`
+	MemoryLimit  int64               `json:"memoryLimit"`
+	MemoryStat   map[string]int64    `json:"memoryStat"`
+	ServiceBytes []CgroupV1BlkioStat `json:"serviceBytes"`
+	Serviced     []CgroupV1BlkioStat `json:"serviced"`
+}
+
+type EffectiveLimits struct {
+	CgroupMode  string  `json:"cgroupMode"`
+	HostCPUs    int64   `json:"hostCpus"`
+	CPUSetCPUs  int64   `json:"cpusetCpus"`
+	CPUQuota    float64 `json:"cpuQuota"`
+	CPUs        float64 `json:"cpus"`
+	HostMemory  int64   `json:"hostMemory"`
+	MemoryLimit int64   `json:"memoryLimit"`
+	MemoryUsage int64   `json:"memoryUsage"`
+	Memory      int64   `json:"memory"`
+}
+
+type Environment struct {
+	Container    bool   `json:"container"`
+	Runtime      string `json:"runtime"`
+	ContainerId  string `json:"containerId"`
+	Kubernetes   bool   `json:"kubernetes"`
+	PodUid       string `json:"podUid"`
+	WSL          bool   `json:"wsl"`
+	Virtualized  bool   `json:"virtualized"`
+	Hypervisor   string `json:"hypervisor"`
+	SystemVendor string `json:"systemVendor"`
+	ProductName  string `json:"productName"`
+}
+
+type MetricLabel struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type Metric struct {
+	Labels []MetricLabel `json:"labels"`
+	Value  float64       `json:"value"`
+}
+
+type MetricFamily struct {
+	Name    string   `json:"name"`
+	Help    string   `json:"help"`
+	Type    string   `json:"type"`
+	Metrics []Metric `json:"metrics"`
+}
+
+type MetricFamilies []MetricFamily
+
+type Snapshot struct {
+	Timestamp         time.Time          `json:"timestamp"`
+	UName             *UName             `json:"uname,omitempty"`
+	SystemInformation *SystemInformation `json:"systemInformation,omitempty"`
+	Stat              *Stat              `json:"stat,omitempty"`
+	MemInfo           *MemInfo           `json:"memInfo,omitempty"`
+	Load              *Load              `json:"load,omitempty"`
+	Uptime            *Uptime            `json:"uptime,omitempty"`
+	CPUInformation    *CPUInformation    `json:"cpuInformation,omitempty"`
+	NetworkStats      NetworkStats       `json:"networkStats,omitempty"`
+	NetworkInterfaces NetworkInterfaces  `json:"networkInterfaces,omitempty"`
+	DiskStats         DiskStats          `json:"diskStats,omitempty"`
+	Mounts            Mounts             `json:"mounts,omitempty"`
+	Errors            map[string]string  `json:"errors,omitempty"`
 }
@@ -1,9 +1,21 @@
 package sysinfo_go
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"syscall"
 	"testing"
+	"testing/fstest"
+	"time"
 )
 
 func TestGetNetworkInterfaces(t *testing.T) {
@@ -134,3 +146,897 @@ func TestGetNetworkInterfaces(t *testing.T) {
 	}
 	fmt.Println(string(data))
 }
+
+func TestCollector(t *testing.T) {
+	fsys := fstest.MapFS{
+		"host/proc/loadavg": &fstest.MapFile{Data: []byte("0.50 0.25 0.10 1/100 4242\n")},
+		"host/proc/1/stat":  &fstest.MapFile{Data: []byte("1 (init) S 0\n")},
+		"host/proc/42/stat": &fstest.MapFile{Data: []byte("42 (sh) S 1\n")},
+	}
+	collector := NewCollector(fsys, "/host/proc", "/host/sys")
+
+	load, err := collector.GetLoadAvg()
+	if nil != err {
+		t.Fatal(err)
+	}
+	if load.Load1 != 0.50 || load.Load5 != 0.25 || load.Load15 != 0.10 || load.Runnable != 1 || load.Entities != 100 {
+		t.Errorf("unexpected load: %+v", load)
+	}
+
+	processes, err := collector.ListProcessId()
+	if nil != err {
+		t.Fatal(err)
+	}
+	if len(processes) != 2 {
+		t.Errorf("unexpected processes: %v", processes)
+	}
+
+	elem := []string{MemInfoFile}
+	if name := collector._ProcPath(elem...); name != "host/proc/meminfo" || elem[0] != MemInfoFile {
+		t.Errorf("unexpected path %q, argument %q", name, elem[0])
+	}
+	if name := collector._ProcPath("/procfoo"); name != "host/proc/procfoo" {
+		t.Errorf("unexpected path %q", name)
+	}
+	if name := collector._SysPath(SysDirectory); name != "host/sys" {
+		t.Errorf("unexpected path %q", name)
+	}
+}
+
+func TestFastConversions(t *testing.T) {
+	if data := FastStringToBytes("meminfo"); string(data) != "meminfo" || cap(data) != len(data) {
+		t.Errorf("unexpected bytes %q", data)
+	}
+	if value := FastBytesToString([]byte("stat")); value != "stat" {
+		t.Errorf("unexpected string %q", value)
+	}
+}
+
+func TestSystemInformation(t *testing.T) {
+	si := &syscall.Sysinfo_t{Uptime: 90, Totalram: 4, Freeram: 2, Sharedram: 1, Totalswap: 8, Freeswap: 3, Unit: 4096, Procs: 7}
+	si.Loads[0] = 1 << 16
+	info := _SystemInformation(si, time.Unix(1000, 0))
+	if info.TotalRam != 4*4096 || info.AvailableRam != 2*4096 || info.SharedRam != 4096 ||
+		info.TotalSwap != 8*4096 || info.AvailableSwap != 3*4096 || info.Processes != 7 {
+		t.Errorf("unexpected information: %+v", info)
+	}
+	if info.Uptime != 90*time.Second || info.BootTime.Unix() != 910 || info.Loads.Load1 != 1 {
+		t.Errorf("unexpected uptime: %+v", info)
+	}
+	si.Unit = 0
+	if info := _SystemInformation(si, time.Unix(1000, 0)); info.TotalRam != 4 {
+		t.Errorf("unexpected information without unit: %+v", info)
+	}
+
+	fsys := fstest.MapFS{
+		"proc/meminfo": &fstest.MapFile{Data: []byte("MemTotal: 8192 kB\nMemFree: 1024 kB\nMemAvailable: 4096 kB\n")},
+		"proc/loadavg": &fstest.MapFile{Data: []byte("0.50 0.25 0.10 2/70000 4242\n")},
+	}
+	info, err := NewCollector(fsys, "proc", "sys").GetSystemInformation()
+	if nil != err {
+		t.Fatal(err)
+	}
+	if info.AvailableRam != 4096*1024 || info.Processes != 70000 || info.Loads.Runnable != 2 {
+		t.Errorf("unexpected information: %+v", info)
+	}
+}
+
+func TestParseVMStat(t *testing.T) {
+	vm, err := _ParseVMStat([]byte("pgfault 100\npgmajfault 7\noom_kill 1\nnr_unknown_counter 9\n"))
+	if nil != err {
+		t.Fatal(err)
+	}
+	if vm.PageFault != 100 || vm.PageMajorFault != 7 || vm.OOMKill != 1 {
+		t.Errorf("unexpected vmstat: %+v", vm)
+	}
+	if vm.Others["nr_unknown_counter"] != 9 {
+		t.Errorf("unexpected others: %v", vm.Others)
+	}
+}
+
+func TestGetProcess(t *testing.T) {
+	process, err := GetProcess(os.Getpid())
+	if nil != err {
+		t.Fatal(err)
+	}
+	if process.Pid != os.Getpid() || process.ParentPid != os.Getppid() {
+		t.Errorf("unexpected process: %+v", process)
+	}
+	data, err := json.MarshalIndent(process, "", "    ")
+	if nil != err {
+		t.Error(err)
+	}
+	fmt.Println(string(data))
+
+	if _, err := GetProcess(-1); err != ErrProcessNotFound {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	stat := "42 (a (b) c) S 1 42 42 0 -1 4194304 10 0 2 0 150 50 0 0 20 0 3 0 500 1048576 10 0\n"
+	process, err = _ParseProcessStat([]byte(stat), 1000)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if process.Name != "a (b) c" || process.Threads != 3 || process.UserTime != 1.5 {
+		t.Errorf("unexpected process: %+v", process)
+	}
+	if process.StartTime.Unix() != 1005 {
+		t.Errorf("unexpected start time: %v", process.StartTime)
+	}
+	// Three years of uptime at USER_HZ overflow nanoseconds multiplied first.
+	late := "42 (sh) S 1 42 42 0 -1 4194304 10 0 2 0 150 50 0 0 20 0 3 0 10000000000 1048576 10 0\n"
+	if process, err := _ParseProcessStat([]byte(late), 1000); nil != err {
+		t.Fatal(err)
+	} else if process.StartTime.Unix() != 100001000 {
+		t.Errorf("unexpected late start time: %v", process.StartTime)
+	}
+	malformed := "42 (sh) S 1 42 42 0 -1 4194304 10 0 2 0 x 50 0 0 20 0 3 0 500 1048576 10 0\n"
+	if _, err := _ParseProcessStat([]byte(malformed), 1000); nil == err {
+		t.Error("expected error for malformed utime")
+	}
+}
+
+func TestCalculateCPUUsage(t *testing.T) {
+	previous := &Stat{CPUStats: []CPUStat{
+		{CPUId: "cpu", User: 100, System: 100, Idle: 800},
+		{CPUId: "cpu0", User: 50, System: 50, Idle: 400},
+		{CPUId: "cpu1", User: 50, System: 50, Idle: 400},
+	}}
+	current := &Stat{CPUStats: []CPUStat{
+		{CPUId: "cpu", User: 150, System: 150, Idle: 900},
+		{CPUId: "cpu0", User: 100, System: 100, Idle: 500},
+		{CPUId: "cpu2", User: 10, System: 10, Idle: 80},
+	}}
+	usage := CalculateCPUUsage(previous, current)
+	if usage.Total.Usage != 50 || usage.Total.User != 25 {
+		t.Errorf("unexpected total: %+v", usage.Total)
+	}
+	if len(usage.CPUs) != 1 || usage.CPUs[0].CPUId != "cpu0" || usage.CPUs[0].Idle != 50 {
+		t.Errorf("unexpected cpus: %+v", usage.CPUs)
+	}
+}
+
+func TestParseInterrupts(t *testing.T) {
+	data := "           CPU0       CPU1\n" +
+		"  0:         40          2   IO-APIC   2-edge      timer\n" +
+		" 27:         10         20  GICv3  30 Level     arch_timer\n" +
+		" 36:      10384          0   PCI-MSIX-0000:00:02.0   1-edge      virtio1-req.0, eth0\n" +
+		"NMI:          1          3   Non-maskable interrupts\n" +
+		"ERR:          0\n"
+	stats, err := _ParseInterrupts([]byte(data))
+	if nil != err {
+		t.Fatal(err)
+	}
+	if len(stats.CPUs) != 2 || len(stats.Interrupts) != 5 {
+		t.Fatalf("unexpected interrupts: %+v", stats)
+	}
+	timer := stats.Interrupts[0]
+	if timer.Total != 42 || timer.Chip != "IO-APIC" || timer.HardwareIRQ != "2" || timer.Trigger != "edge" {
+		t.Errorf("unexpected interrupt: %+v", timer)
+	}
+	if stats.Interrupts[1].HardwareIRQ != "30" || stats.Interrupts[1].Trigger != "Level" {
+		t.Errorf("unexpected interrupt: %+v", stats.Interrupts[1])
+	}
+	if devices := stats.Interrupts[2].Devices; len(devices) != 2 || devices[1] != "eth0" {
+		t.Errorf("unexpected devices: %v", devices)
+	}
+	if stats.Interrupts[3].Description != "Non-maskable interrupts" {
+		t.Errorf("unexpected interrupt: %+v", stats.Interrupts[3])
+	}
+
+	if _, err := GetInterrupts(); nil != err {
+		t.Error(err)
+	}
+
+	rates := CalculateInterruptRates(stats, stats, time.Second)
+	if len(rates) != 5 || rates[0].Total != 0 {
+		t.Errorf("unexpected rates: %+v", rates)
+	}
+}
+
+func TestParseStat(t *testing.T) {
+	data := "cpu  10 0 10 80 0 0 0 0 0 0\n" +
+		"intr 30 10 0 20\n" +
+		"ctxt 1234\n" +
+		"btime 1000\n" +
+		"softirq 55 1 2 3 4 5 6 7 8 9 10\n"
+	stat, err := _ParseStat([]byte(data))
+	if nil != err {
+		t.Fatal(err)
+	}
+	if stat.Interrupts != 30 || len(stat.InterruptCounts) != 3 || stat.InterruptCounts[2] != 20 {
+		t.Errorf("unexpected interrupts: %d %v", stat.Interrupts, stat.InterruptCounts)
+	}
+	if stat.ContextSwitches != 1234 || stat.BootTime != 1000 {
+		t.Errorf("unexpected stat: %+v", stat)
+	}
+	if stat.SoftIRQs.Total != 55 || stat.SoftIRQs.NetRx != 4 || stat.SoftIRQs.RCU != 10 {
+		t.Errorf("unexpected softirqs: %+v", stat.SoftIRQs)
+	}
+}
+
+func TestParseNetworkStats(t *testing.T) {
+	data := "Inter-|   Receive                                                |  Transmit\n" +
+		" face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed\n" +
+		"  eth0: 1000 10 1 2 3 4 5 6 2000 20 7 8 9 10 11 12\n"
+	stats, err := _ParseNetworkStats([]byte(data))
+	if nil != err {
+		t.Fatal(err)
+	}
+	if len(stats) != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	stat := stats[0]
+	if stat.Interface != "eth0" || stat.ReceivedDropped != 2 || stat.ReceivedMulticast != 6 ||
+		stat.TransmittedErrors != 7 || stat.TransmittedCompressed != 12 {
+		t.Errorf("unexpected stat: %+v", stat)
+	}
+}
+
+func TestNetworkRateSampler(t *testing.T) {
+	var (
+		sampler = NewNetworkRateSampler(nil)
+		start   = time.Unix(1000, 0)
+	)
+	rates := sampler.Update(NetworkStats{
+		{Interface: "eth0", ReceivedBytes: math.MaxUint32 - 99, TransmittedBytes: math.MaxUint32 - 49},
+		{Interface: "eth1", ReceivedBytes: 100},
+	}, start)
+	if len(rates) != 0 {
+		t.Errorf("unexpected rates: %+v", rates)
+	}
+	rates = sampler.Update(NetworkStats{
+		{Interface: "eth0", ReceivedBytes: 100, TransmittedBytes: 150},
+		{Interface: "eth2", ReceivedBytes: 100},
+	}, start.Add(2*time.Second))
+	if len(rates) != 1 || rates[0].Interface != "eth0" {
+		t.Fatalf("unexpected rates: %+v", rates)
+	}
+	if rates[0].ReceivedBytes != 100 || rates[0].TransmittedBytes != 100 {
+		t.Errorf("unexpected rate: %+v", rates[0])
+	}
+
+	// A reset from below 4 GiB is not a 32 bit wrap.
+	if increase := _CounterIncrease(3000000000, 1024); increase != 1024 {
+		t.Errorf("unexpected increase after reset: %d", increase)
+	}
+	if increase := _CounterIncrease(8000000000, 1024); increase != 1024 {
+		t.Errorf("unexpected increase after 64 bit reset: %d", increase)
+	}
+}
+
+func TestCalculateDiskRates(t *testing.T) {
+	disks, err := _ParseDiskStats([]byte(
+		"   8       0 sda 100 0 800 50 200 0 1600 150 0 400 200 0 0 0 0\n" +
+			"   8       0 sda 200 0 1600 150 300 0 2400 250 1 900 600 0 0 0 0\n"))
+	if nil != err {
+		t.Fatal(err)
+	}
+	if len(disks) != 2 {
+		t.Fatalf("unexpected disks: %+v", disks)
+	}
+	rates := CalculateDiskRates(disks[:1], disks[1:], time.Second)
+	if len(rates) != 1 {
+		t.Fatalf("unexpected rates: %+v", rates)
+	}
+	rate := rates[0]
+	if rate.ReadsPerSecond != 100 || rate.WriteBytes != 800*DiskStatSectorSize {
+		t.Errorf("unexpected throughput: %+v", rate)
+	}
+	if rate.ReadAwait != 1 || rate.Await != 1 || rate.Utilisation != 50 || rate.AverageQueueSize != 0.4 {
+		t.Errorf("unexpected latency: %+v", rate)
+	}
+}
+
+func TestGetMounts(t *testing.T) {
+	data := "36 35 98:0 /mnt1 /mnt\\040two rw,noatime master:1 shared:2 - ext3 /dev/root rw,errors=continue\n" +
+		"23 28 0:22 / /proc rw,relatime - proc proc rw\n"
+	mounts, err := _ParseMountInfo([]byte(data))
+	if nil != err {
+		t.Fatal(err)
+	}
+	if len(mounts) != 2 {
+		t.Fatalf("unexpected mounts: %+v", mounts)
+	}
+	mount := mounts[0]
+	if mount.MountId != 36 || mount.Major != 98 || mount.MountPoint != "/mnt two" ||
+		len(mount.OptionalFields) != 2 || mount.FileSystemType != "ext3" ||
+		mount.Source != "/dev/root" || mount.SuperOptions[1] != "errors=continue" {
+		t.Errorf("unexpected mount: %+v", mount)
+	}
+}
+
+// _StatFSMapFS answers statfs from a table, keyed by mount point relative
+// to the root.
+type _StatFSMapFS struct {
+	fstest.MapFS
+	stats map[string]*FileSystemStat
+}
+
+func (m *_StatFSMapFS) StatFS(name string) (*FileSystemStat, error) {
+	if stat, ok := m.stats[name]; ok {
+		return stat, nil
+	}
+	return nil, fs.ErrNotExist
+}
+
+func TestGetFileSystemUsage(t *testing.T) {
+	mountinfo := []byte(
+		"22 1 8:1 / / rw,relatime - ext4 /dev/sda1 rw\n" +
+			"23 22 0:22 / /proc rw,relatime - proc proc rw\n" +
+			"24 22 8:2 / /data rw,relatime - xfs /dev/sda2 rw\n" +
+			"25 22 0:40 / /mnt/gone rw,relatime - nfs server:/export rw\n")
+	fsys := &_StatFSMapFS{
+		MapFS: fstest.MapFS{"host/proc/self/mountinfo": &fstest.MapFile{Data: mountinfo}},
+		stats: map[string]*FileSystemStat{
+			".":        {Type: "ext4", Capacity: 1000, Used: 250},
+			"data":     {Type: "xfs", Capacity: 2000, Used: 500},
+			"mnt/gone": {Type: "nfs", Capacity: 3000},
+		},
+	}
+	usage, err := NewCollector(fsys, "/host/proc", "/host/sys").GetFileSystemUsage()
+	if nil != err {
+		t.Fatal(err)
+	}
+	if len(usage) != 2 || usage[0].Mount.MountPoint != "/" || usage[0].Stat.Type != "ext4" ||
+		usage[1].Mount.Source != "/dev/sda2" || usage[1].Stat.Capacity != 2000 {
+		t.Errorf("unexpected usage: %+v", usage)
+	}
+	if usage, err = NewCollector(fsys, "/host/proc", "/host/sys").GetAllFileSystemUsage(); nil != err {
+		t.Fatal(err)
+	}
+	if len(usage) != 3 || usage[2].Mount.FileSystemType != "nfs" || usage[2].Stat.Capacity != 3000 {
+		t.Errorf("unexpected usage including network filesystems: %+v", usage)
+	}
+
+	collector := NewCollector(fsys.MapFS, "/host/proc", "/host/sys")
+	if _, err := collector.GetFileSystemUsage(); !errors.Is(err, ErrStatFSUnsupported) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestGetFileSystemStat(t *testing.T) {
+	stat, err := GetFileSystemStat("/proc")
+	if nil != err {
+		t.Fatal(err)
+	}
+	if stat.Type != "proc" {
+		t.Errorf("unexpected type: %+v", stat)
+	}
+	if flags := _MountFlags(MountFlagReadOnly | MountFlagNoExec); len(flags) != 2 || flags[0] != "ro" {
+		t.Errorf("unexpected flags: %v", flags)
+	}
+}
+
+func TestParseMemInfo(t *testing.T) {
+	data := "MemTotal:       16384 kB\n" +
+		"Active(anon):     100 kB\n" +
+		"Committed_AS:     200 kB\n" +
+		"HugePages_Total:    4\n" +
+		"NewField:          10 kB\n"
+	mem, err := _ParseMemInfo([]byte(data))
+	if nil != err {
+		t.Fatal(err)
+	}
+	if mem.Total != 16384*1024 || mem.ActiveAnon != 100*1024 || mem.CommittedAS != 200*1024 {
+		t.Errorf("unexpected meminfo: %+v", mem)
+	}
+	if mem.HugePagesTotal != 4 || mem.Others["NewField"] != 10*1024 {
+		t.Errorf("unexpected meminfo: %+v", mem)
+	}
+}
+
+func TestGetPressure(t *testing.T) {
+	fsys := fstest.MapFS{
+		"proc/pressure/cpu": &fstest.MapFile{Data: []byte(
+			"some avg10=1.42 avg60=1.24 avg300=1.26 total=14337864\n" +
+				"full avg10=0.00 avg60=0.00 avg300=0.00 total=0\n")},
+		"proc/pressure/irq":                       &fstest.MapFile{Data: []byte("full avg10=0.50 avg60=0.00 avg300=0.00 total=42\n")},
+		"sys/fs/cgroup/app.slice/memory.pressure": &fstest.MapFile{Data: []byte("some avg10=2.00 avg60=0.00 avg300=0.00 total=7\n")},
+	}
+	collector := NewCollector(fsys, "proc", "sys")
+	stats, err := collector.GetPressure()
+	if nil != err {
+		t.Fatal(err)
+	}
+	if stats.CPU.Some.Avg10 != 1.42 || stats.CPU.Some.Total != 14337864 || nil != stats.Memory {
+		t.Errorf("unexpected pressure: %+v", stats)
+	}
+	if nil != stats.IRQ.Some || stats.IRQ.Full.Total != 42 {
+		t.Errorf("unexpected irq pressure: %+v", stats.IRQ)
+	}
+	stats, err = collector.GetCgroupPressure("/app.slice")
+	if nil != err {
+		t.Fatal(err)
+	}
+	if stats.Memory.Some.Avg10 != 2 {
+		t.Errorf("unexpected cgroup pressure: %+v", stats.Memory)
+	}
+}
+
+func TestGetCgroup(t *testing.T) {
+	fsys := fstest.MapFS{
+		"proc/42/cgroup": &fstest.MapFile{Data: []byte("4:memory:/legacy\n0::/app.slice\n")},
+		"sys/fs/cgroup/app.slice/cgroup.controllers": &fstest.MapFile{Data: []byte("cpu memory io pids\n")},
+		"sys/fs/cgroup/app.slice/cpu.stat":           &fstest.MapFile{Data: []byte("usage_usec 300\nuser_usec 200\nsystem_usec 100\n")},
+		"sys/fs/cgroup/app.slice/cpu.max":            &fstest.MapFile{Data: []byte("50000 100000\n")},
+		"sys/fs/cgroup/app.slice/memory.current":     &fstest.MapFile{Data: []byte("4096\n")},
+		"sys/fs/cgroup/app.slice/memory.max":         &fstest.MapFile{Data: []byte("max\n")},
+		"sys/fs/cgroup/app.slice/memory.events":      &fstest.MapFile{Data: []byte("low 0\nhigh 0\nmax 3\noom 1\noom_kill 1\n")},
+		"sys/fs/cgroup/app.slice/io.stat":            &fstest.MapFile{Data: []byte("8:0 rbytes=1024 wbytes=2048 rios=1 wios=2 dbytes=0 dios=0\n8:16 rbytes=512 wbytes=0 rios=1 wios=0 dbytes=0 dios=0 cost.vrate=100.00 cost.usage=1.5 depth=max avg_lat=0\n")},
+		"sys/fs/cgroup/app.slice/pids.current":       &fstest.MapFile{Data: []byte("7\n")},
+		"sys/fs/cgroup/app.slice/pids.max":           &fstest.MapFile{Data: []byte("100\n")},
+	}
+	collector := NewCollector(fsys, "proc", "sys")
+	cgroup, err := collector.GetProcessCgroup(42)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if cgroup.Path != "/app.slice" || cgroup.CPUStat.Usage != 300 || cgroup.CPUMax.Quota != 50000 {
+		t.Errorf("unexpected cgroup: %+v", cgroup)
+	}
+	if cgroup.MemoryCurrent != 4096 || cgroup.MemoryMax != CgroupUnlimited || cgroup.MemoryEvents.OOMKill != 1 {
+		t.Errorf("unexpected memory: %+v", cgroup)
+	}
+	if len(cgroup.IOStats) != 2 || cgroup.IOStats[0].WriteBytes != 2048 || cgroup.IOStats[1].ReadBytes != 512 || cgroup.PidsMax != 100 {
+		t.Errorf("unexpected io or pids: %+v", cgroup)
+	}
+
+	// The cgroup2 mount shows the group of the container, not the root of
+	// the hierarchy.
+	fsys = fstest.MapFS{
+		"proc/self/mountinfo":                  &fstest.MapFile{Data: []byte("42 28 0:38 /kubepods/pod1 /sys/fs/cgroup rw - cgroup2 cgroup2 rw\n")},
+		"proc/43/cgroup":                       &fstest.MapFile{Data: []byte("0::/kubepods/pod1/app\n")},
+		"proc/44/cgroup":                       &fstest.MapFile{Data: []byte("0::/kubepods/pod2\n")},
+		"sys/fs/cgroup/app/cgroup.controllers": &fstest.MapFile{Data: []byte("memory\n")},
+		"sys/fs/cgroup/app/memory.current":     &fstest.MapFile{Data: []byte("8192\n")},
+	}
+	collector = NewCollector(fsys, "proc", "sys")
+	if cgroup, err = collector.GetProcessCgroup(43); nil != err {
+		t.Fatal(err)
+	}
+	if cgroup.Path != "/kubepods/pod1/app" || cgroup.MemoryCurrent != 8192 {
+		t.Errorf("unexpected cgroup below a mount root: %+v", cgroup)
+	}
+	if _, err := collector.GetProcessCgroup(44); nil == err {
+		t.Error("expected error for a group outside the mount")
+	}
+}
+
+func TestGetCgroupV1(t *testing.T) {
+	fsys := fstest.MapFS{
+		"proc/self/mountinfo": &fstest.MapFile{Data: []byte(
+			"33 32 0:29 / /sys/fs/cgroup/cpu,cpuacct rw - cgroup cgroup rw,cpu,cpuacct\n" +
+				"36 32 0:32 /docker/abc /sys/fs/cgroup/memory rw - cgroup cgroup rw,memory\n" +
+				"39 32 0:35 / /sys/fs/cgroup/blkio rw - cgroup cgroup rw,blkio\n")},
+		"proc/42/cgroup": &fstest.MapFile{Data: []byte(
+			"3:cpu,cpuacct:/docker/abc\n2:memory:/docker/abc\n1:blkio:/docker/abc\n")},
+		"sys/fs/cgroup/cpu,cpuacct/docker/abc/cpuacct.usage":     &fstest.MapFile{Data: []byte("123456789\n")},
+		"sys/fs/cgroup/cpu,cpuacct/docker/abc/cpu.cfs_quota_us":  &fstest.MapFile{Data: []byte("50000\n")},
+		"sys/fs/cgroup/cpu,cpuacct/docker/abc/cpu.cfs_period_us": &fstest.MapFile{Data: []byte("100000\n")},
+		"sys/fs/cgroup/memory/memory.usage_in_bytes":             &fstest.MapFile{Data: []byte("4096\n")},
+		"sys/fs/cgroup/memory/memory.limit_in_bytes":             &fstest.MapFile{Data: []byte("9223372036854771712\n")},
+		"sys/fs/cgroup/blkio/docker/abc/blkio.throttle.io_service_bytes": &fstest.MapFile{Data: []byte(
+			"8:0 Read 1024\n8:0 Write 2048\n8:0 Total 3072\nTotal 3072\n")},
+	}
+	collector := NewCollector(fsys, "proc", "sys")
+	if mode, err := collector.GetCgroupMode(); nil != err || mode != CgroupModeV1 {
+		t.Errorf("unexpected mode: %s %v", mode, err)
+	}
+	cgroup, err := collector.GetProcessCgroupV1(42)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if cgroup.CPUUsage != 123456789 || cgroup.CPUQuota != 50000 || cgroup.CPUPeriod != 100000 {
+		t.Errorf("unexpected cpu: %+v", cgroup)
+	}
+	if cgroup.MemoryUsage != 4096 || cgroup.MemoryLimit != CgroupUnlimited {
+		t.Errorf("unexpected memory: %+v", cgroup)
+	}
+	if len(cgroup.ServiceBytes) != 1 || cgroup.ServiceBytes[0].Total != 3072 {
+		t.Errorf("unexpected blkio: %+v", cgroup.ServiceBytes)
+	}
+
+	// /docker/abcdef lies outside the memory mount rooted at /docker/abc.
+	mounts, err := collector.GetMounts()
+	if nil != err {
+		t.Fatal(err)
+	}
+	directories := _CgroupV1Directories(mounts, ProcessCgroups{
+		{HierarchyId: 2, Controllers: []string{"memory"}, Path: "/docker/abcdef/child"},
+		{HierarchyId: 1, Controllers: []string{"blkio"}, Path: "/docker/abcdef/child"},
+	})
+	if _, ok := directories["memory"]; ok || directories["blkio"] != "/sys/fs/cgroup/blkio/docker/abcdef/child" {
+		t.Errorf("unexpected directories: %v", directories)
+	}
+	directories = _CgroupV1Directories(mounts, ProcessCgroups{
+		{HierarchyId: 2, Controllers: []string{"memory"}, Path: "/docker/abc/child"},
+	})
+	if directories["memory"] != "/sys/fs/cgroup/memory/child" {
+		t.Errorf("unexpected directories: %v", directories)
+	}
+}
+
+func TestGetEffectiveLimits(t *testing.T) {
+	fsys := fstest.MapFS{
+		"proc/self/mountinfo":                                   &fstest.MapFile{Data: []byte("42 28 0:38 / /sys/fs/cgroup rw - cgroup2 cgroup2 rw\n")},
+		"proc/self/cgroup":                                      &fstest.MapFile{Data: []byte("0::/kubepods/pod1/app\n")},
+		"proc/meminfo":                                          &fstest.MapFile{Data: []byte("MemTotal: 8388608 kB\nMemFree: 4194304 kB\nMemAvailable: 6291456 kB\n")},
+		"sys/devices/system/cpu/online":                         &fstest.MapFile{Data: []byte("0-7\n")},
+		"sys/fs/cgroup/cgroup.controllers":                      &fstest.MapFile{Data: []byte("cpu memory\n")},
+		"sys/fs/cgroup/kubepods/pod1/memory.max":                &fstest.MapFile{Data: []byte("1073741824\n")},
+		"sys/fs/cgroup/kubepods/pod1/cpu.max":                   &fstest.MapFile{Data: []byte("max 100000\n")},
+		"sys/fs/cgroup/kubepods/pod1/app/memory.max":            &fstest.MapFile{Data: []byte("max\n")},
+		"sys/fs/cgroup/kubepods/pod1/app/memory.current":        &fstest.MapFile{Data: []byte("268435456\n")},
+		"sys/fs/cgroup/kubepods/pod1/app/cpu.max":               &fstest.MapFile{Data: []byte("150000 100000\n")},
+		"sys/fs/cgroup/kubepods/pod1/app/cpuset.cpus.effective": &fstest.MapFile{Data: []byte("0-3\n")},
+	}
+	collector := NewCollector(fsys, "proc", "sys")
+	limits, err := collector.GetEffectiveLimits()
+	if nil != err {
+		t.Fatal(err)
+	}
+	if limits.CgroupMode != CgroupModeV2 || limits.HostCPUs != 8 || limits.CPUSetCPUs != 4 || limits.CPUs != 1.5 {
+		t.Errorf("unexpected cpu limits: %+v", limits)
+	}
+	if limits.MemoryLimit != 1<<30 || limits.Memory != 1<<30 || limits.MemoryUsage != 1<<28 {
+		t.Errorf("unexpected memory limits: %+v", limits)
+	}
+	mem, err := collector.GetContainerMemInfo()
+	if nil != err {
+		t.Fatal(err)
+	}
+	if mem.Total != 1<<30 || mem.Available != 3<<28 {
+		t.Errorf("unexpected meminfo: %+v", mem)
+	}
+
+	// With cgroupns=host the mount shows the group of the container, not the
+	// root of the hierarchy.
+	fsys = fstest.MapFS{
+		"proc/self/mountinfo":                        &fstest.MapFile{Data: []byte("42 28 0:38 /kubepods/pod1 /sys/fs/cgroup rw - cgroup2 cgroup2 rw\n")},
+		"proc/self/cgroup":                           &fstest.MapFile{Data: []byte("0::/kubepods/pod1/app\n")},
+		"proc/meminfo":                               &fstest.MapFile{Data: []byte("MemTotal: 8388608 kB\nMemFree: 4194304 kB\nMemAvailable: 6291456 kB\n")},
+		"sys/devices/system/cpu/online":              &fstest.MapFile{Data: []byte("0-7\n")},
+		"sys/fs/cgroup/cgroup.controllers":           &fstest.MapFile{Data: []byte("cpu memory\n")},
+		"sys/fs/cgroup/memory.max":                   &fstest.MapFile{Data: []byte("1073741824\n")},
+		"sys/fs/cgroup/app/memory.max":               &fstest.MapFile{Data: []byte("max\n")},
+		"sys/fs/cgroup/app/memory.current":           &fstest.MapFile{Data: []byte("268435456\n")},
+		"sys/fs/cgroup/app/cpu.max":                  &fstest.MapFile{Data: []byte("150000 100000\n")},
+		"sys/fs/cgroup/app/cpuset.cpus.effective":    &fstest.MapFile{Data: []byte("0-3\n")},
+		"sys/fs/cgroup/kubepods/pod1/app/memory.max": &fstest.MapFile{Data: []byte("1\n")},
+		"sys/fs/cgroup/kubepods/pod1/app/cpu.max":    &fstest.MapFile{Data: []byte("1 100000\n")},
+	}
+	limits, err = NewCollector(fsys, "proc", "sys").GetEffectiveLimits()
+	if nil != err {
+		t.Fatal(err)
+	}
+	if limits.CPUSetCPUs != 4 || limits.CPUs != 1.5 || limits.MemoryLimit != 1<<30 || limits.MemoryUsage != 1<<28 {
+		t.Errorf("unexpected limits below a mount root: %+v", limits)
+	}
+
+	// A group outside the mounted tree has no visible limits.
+	fsys["proc/self/cgroup"] = &fstest.MapFile{Data: []byte("0::/kubepods/pod2\n")}
+	limits, err = NewCollector(fsys, "proc", "sys").GetEffectiveLimits()
+	if nil != err {
+		t.Fatal(err)
+	}
+	if limits.MemoryLimit != CgroupUnlimited || limits.CPUs != 8 {
+		t.Errorf("unexpected limits outside the mount root: %+v", limits)
+	}
+}
+
+func TestDetectEnvironment(t *testing.T) {
+	const id = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	fsys := fstest.MapFS{
+		"proc/self/cgroup":              &fstest.MapFile{Data: []byte("0::/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod1b2c3d4e_0000_1111_2222_333344445555.slice/cri-containerd-" + id + ".scope\n")},
+		"proc/self/mountinfo":           &fstest.MapFile{Data: []byte("42 28 0:38 / /sys/fs/cgroup rw - cgroup2 cgroup2 rw\n")},
+		"proc/self/environ":             &fstest.MapFile{Data: []byte("PATH=/bin\x00KUBERNETES_SERVICE_HOST=10.0.0.1\x00")},
+		"proc/cpuinfo":                  &fstest.MapFile{Data: []byte("processor\t: 0\nflags\t\t: fpu vme hypervisor\n\n")},
+		"proc/sys/kernel/osrelease":     &fstest.MapFile{Data: []byte("6.1.0\n")},
+		"sys/class/dmi/id/sys_vendor":   &fstest.MapFile{Data: []byte("QEMU\n")},
+		"sys/class/dmi/id/product_name": &fstest.MapFile{Data: []byte("Standard PC (Q35 + ICH9, 2009)\n")},
+	}
+	env, err := NewCollector(fsys, "proc", "sys").DetectEnvironment()
+	if nil != err {
+		t.Fatal(err)
+	}
+	if !env.Container || env.Runtime != ContainerContainerd || env.ContainerId != id {
+		t.Errorf("unexpected container: %+v", env)
+	}
+	if !env.Kubernetes || env.PodUid != "1b2c3d4e-0000-1111-2222-333344445555" {
+		t.Errorf("unexpected pod: %+v", env)
+	}
+	if env.WSL || !env.Virtualized || env.Hypervisor != "kvm" {
+		t.Errorf("unexpected virtualization: %+v", env)
+	}
+
+	fsys = fstest.MapFS{
+		".dockerenv":                &fstest.MapFile{},
+		"proc/self/cgroup":          &fstest.MapFile{Data: []byte("0::/\n")},
+		"proc/self/mountinfo":       &fstest.MapFile{Data: []byte("500 490 8:1 /var/lib/docker/containers/" + id + "/hostname /etc/hostname rw - ext4 /dev/sda1 rw\n")},
+		"proc/sys/kernel/osrelease": &fstest.MapFile{Data: []byte("5.15.90.1-microsoft-standard-WSL2\n")},
+	}
+	env, err = NewCollector(fsys, "proc", "sys").DetectEnvironment()
+	if nil != err {
+		t.Fatal(err)
+	}
+	if !env.Container || env.Runtime != ContainerDocker || env.ContainerId != id || env.Kubernetes {
+		t.Errorf("unexpected container: %+v", env)
+	}
+	if !env.WSL || !env.Virtualized {
+		t.Errorf("unexpected virtualization: %+v", env)
+	}
+}
+
+func TestPrometheusExporter(t *testing.T) {
+	fsys := fstest.MapFS{
+		"proc/stat":      &fstest.MapFile{Data: []byte("cpu  300 0 200 1000 0 0 0 0 0 0\ncpu0 300 0 200 1000 0 0 0 0 0 0\nctxt 42\nbtime 1700000000\nprocesses 7\n")},
+		"proc/meminfo":   &fstest.MapFile{Data: []byte("MemTotal: 1024 kB\nMemFree: 512 kB\n")},
+		"proc/loadavg":   &fstest.MapFile{Data: []byte("0.50 0.25 0.10 2/300 1234\n")},
+		"proc/diskstats": &fstest.MapFile{Data: []byte("   8       0 sda 10 0 8 1500 0 0 0 0 0 0 0\n")},
+		"proc/net/dev":   &fstest.MapFile{Data: []byte("Inter-|   Receive                                                |  Transmit\n face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop colls carrier compressed\n eth\"0: 100 1 0 0 0 0 0 0 200 2 0 0 0 0 0 0\n")},
+	}
+	exporter := NewPrometheusExporter(NewCollector(fsys, "proc", "sys"), "")
+	recorder := httptest.NewRecorder()
+	exporter.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if recorder.Header().Get("Content-Type") != PrometheusContentType {
+		t.Errorf("unexpected content type %q", recorder.Header().Get("Content-Type"))
+	}
+	body := recorder.Body.String()
+	for _, expected := range []string{
+		"# TYPE sysinfo_cpu_seconds_total counter\n",
+		"sysinfo_cpu_seconds_total{cpu=\"0\",mode=\"user\"} 3\n",
+		"# TYPE sysinfo_memory_total_bytes gauge\n",
+		"sysinfo_memory_total_bytes 1.048576e+06\n",
+		"sysinfo_load1 0.5\n",
+		"sysinfo_disk_read_bytes_total{device=\"sda\"} 4096\n",
+		"sysinfo_disk_read_time_seconds_total{device=\"sda\"} 1.5\n",
+		"sysinfo_network_receive_bytes_total{interface=\"eth\\\"0\"} 100\n",
+		"sysinfo_scrape_collector_success{collector=\"pressure\"} 0\n",
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("missing %q in:\n%s", expected, body)
+		}
+	}
+}
+
+func TestEncoders(t *testing.T) {
+	options := &EncoderOptions{
+		Measurements: map[string]string{MeasurementDisk: "disk io"},
+		Tags:         map[string]string{TagHost: "web-1"},
+		Timestamp:    time.Unix(1700000000, 5),
+	}
+	var (
+		load  = &Load{Load1: 0.5, Load5: 0.25, Load15: 0.125, Runnable: 2, Entities: 300}
+		disks = DiskStats{{Major: 8, Device: "sda", ReadsComplete: 10, SectorsRead: 8}}
+	)
+	var buffer strings.Builder
+	if err := EncodeInflux(&buffer, options, load, disks); nil != err {
+		t.Fatal(err)
+	}
+	expected := "load,host=web-1 load1=0.5,load5=0.25,load15=0.125,runnable=2i,entities=300i 1700000000000000005\n"
+	if lines := strings.Split(buffer.String(), "\n"); lines[0]+"\n" != expected {
+		t.Errorf("unexpected load point %q", lines[0])
+	} else if !strings.HasPrefix(lines[1], `disk\ io,device=sda,host=web-1 major=8i,minor=0i,readsComplete=10i,`) {
+		t.Errorf("unexpected disk point %q", lines[1])
+	}
+
+	buffer.Reset()
+	if err := EncodeOpenMetrics(&buffer, options, load, disks); nil != err {
+		t.Fatal(err)
+	}
+	body := buffer.String()
+	for _, expected := range []string{
+		"sysinfo_load1{host=\"web-1\"} 0.5 1700000000.000000005\n",
+		"# TYPE sysinfo_disk_read_bytes counter\n# UNIT sysinfo_disk_read_bytes bytes\n",
+		"sysinfo_disk_read_bytes_total{device=\"sda\",host=\"web-1\"} 4096 1700000000.000000005\n",
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("missing %q in:\n%s", expected, body)
+		}
+	}
+	if !strings.HasSuffix(body, "# EOF\n") {
+		t.Errorf("missing EOF in:\n%s", body)
+	}
+	if err := EncodeInflux(&buffer, options, "load"); nil == err {
+		t.Error("expected error encoding a string")
+	}
+	_, fields := _InfluxPoint(struct {
+		Signed   int64  `json:"signed"`
+		Unsigned uint64 `json:"unsigned"`
+	}{-1, 1 << 63})
+	if strings.Join(fields, ",") != "signed=-1i,unsigned=9223372036854775808u" {
+		t.Errorf("unexpected fields %q", fields)
+	}
+
+	// Only counters may carry the _total suffix.
+	for _, value := range []interface{}{&Stat{}, &MemInfo{}, &VMStat{}, load, disks, NetworkStats{{Interface: "eth0"}}, &FileSystemStat{}, &PressureStats{}} {
+		families, err := _MetricFamiliesOf(value)
+		if nil != err {
+			t.Fatal(err)
+		}
+		for _, family := range families {
+			if family.Type != MetricCounter && strings.HasSuffix(family.Name, "_total") {
+				t.Errorf("%s family %s named like a counter", family.Type, family.Name)
+			}
+		}
+	}
+}
+
+// _BlockingFS blocks opening one file until released, like a source stuck
+// on a hung filesystem.
+type _BlockingFS struct {
+	files   fstest.MapFS
+	name    string
+	release chan struct{}
+}
+
+func (b *_BlockingFS) Open(name string) (fs.File, error) {
+	if name == b.name {
+		<-b.release
+	}
+	return b.files.Open(name)
+}
+
+func TestGetSnapshot(t *testing.T) {
+	fsys := fstest.MapFS{
+		"proc/loadavg": &fstest.MapFile{Data: []byte("0.50 0.25 0.10 1/100 4242\n")},
+		"proc/uptime":  &fstest.MapFile{Data: []byte("100.00 50.00\n")},
+	}
+	collector := NewCollector(fsys, "proc", "sys")
+	snapshot, err := collector.GetSnapshot(&SnapshotOptions{
+		Timeout: time.Second,
+		Sources: []string{SnapshotLoad, SnapshotUptime, SnapshotMemInfo},
+	})
+	if nil != err {
+		t.Fatal(err)
+	}
+	if nil == snapshot.Load || snapshot.Load.Load1 != 0.50 || nil == snapshot.Uptime || snapshot.Uptime.Total != 100 {
+		t.Errorf("unexpected snapshot: %+v", snapshot)
+	}
+	if _, ok := snapshot.Errors[SnapshotMemInfo]; !ok || len(snapshot.Errors) != 1 || nil != snapshot.MemInfo {
+		t.Errorf("unexpected errors: %v", snapshot.Errors)
+	}
+	if _, err := collector.GetSnapshot(&SnapshotOptions{Sources: []string{SnapshotStat}}); nil == err {
+		t.Error("expected error when every source fails")
+	}
+	if _, err := collector.GetSnapshot(&SnapshotOptions{Sources: []string{"bogus"}}); nil == err {
+		t.Error("expected error for unknown source")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := collector.GetSnapshot(&SnapshotOptions{Context: ctx}); nil == err {
+		t.Error("expected error for cancelled context")
+	}
+	// A source missing the deadline is reported while the others are kept.
+	blocking := &_BlockingFS{files: fsys, name: "proc/uptime", release: make(chan struct{})}
+	defer close(blocking.release)
+	started := time.Now()
+	snapshot, err = NewCollector(blocking, "proc", "sys").GetSnapshot(&SnapshotOptions{
+		Timeout: 50 * time.Millisecond,
+		Sources: []string{SnapshotLoad, SnapshotUptime},
+	})
+	if nil != err {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Errorf("snapshot waited %v for the slow source", elapsed)
+	}
+	if nil == snapshot.Load || snapshot.Load.Load1 != 0.50 || nil != snapshot.Uptime {
+		t.Errorf("unexpected snapshot: %+v", snapshot)
+	}
+	if snapshot.Errors[SnapshotUptime] != context.DeadlineExceeded.Error() || len(snapshot.Errors) != 1 {
+		t.Errorf("unexpected errors: %v", snapshot.Errors)
+	}
+}
+
+func TestRecordReplay(t *testing.T) {
+	fsys := fstest.MapFS{
+		"host/proc/stat":       &fstest.MapFile{Data: []byte("cpu  1 2 3 4 5 6 7 8 0 0\nbtime 1700000000\n")},
+		"host/proc/loadavg":    &fstest.MapFile{Data: []byte("0.50 0.25 0.10 1/100 4242\n")},
+		"host/proc/42/stat":    &fstest.MapFile{Data: []byte("42 (sh) S 1 42 42 0 -1 4194304 10 0 2 0 150 50 0 0 20 0 1 0 500 1048576 10 0\n")},
+		"host/proc/42/status":  &fstest.MapFile{Data: []byte("Name:\tsh\nUid:\t0\t0\t0\t0\nGid:\t0\t0\t0\t0\n")},
+		"host/proc/42/cmdline": &fstest.MapFile{Data: []byte("sh\x00")},
+		"host/proc/42/comm":    &fstest.MapFile{Data: []byte("sh\n")},
+		"host/proc/42/cgroup":  &fstest.MapFile{Data: []byte("0::/app.slice\n")},
+		"host/sys/fs/cgroup/app.slice/cgroup.controllers": &fstest.MapFile{Data: []byte("cpu memory\n")},
+		"host/sys/fs/cgroup/cgroup.controllers":           &fstest.MapFile{Data: []byte("cpu memory\n")},
+	}
+	collector := NewCollector(fsys, "/host/proc", "/host/sys")
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	buffer := new(bytes.Buffer)
+	writer := NewTarRecordWriter(buffer)
+	recorder := NewRecorder(collector, writer)
+	for i := 0; i < 2; i++ {
+		if err := recorder.Record(at.Add(time.Duration(i) * time.Second)); nil != err {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); nil != err {
+		t.Fatal(err)
+	}
+	archive, err := ReadTar(buffer)
+	if nil != err {
+		t.Fatal(err)
+	}
+	directory, err := NewDirectoryRecordWriter(t.TempDir())
+	if nil != err {
+		t.Fatal(err)
+	}
+	if err := NewRecorder(collector, directory).Record(at); nil != err {
+		t.Fatal(err)
+	}
+
+	for _, open := range []func() (*Replay, error){
+		func() (*Replay, error) { return NewReplay(archive) },
+		func() (*Replay, error) { return OpenReplay(directory.(*_DirectoryRecordWriter).root) },
+	} {
+		replay, err := open()
+		if nil != err {
+			t.Fatal(err)
+		}
+		if !replay.Time(0).Equal(at) {
+			t.Errorf("unexpected time %v", replay.Time(0))
+		}
+		replayed := replay.Collector(replay.Len() - 1)
+		load, err := replayed.GetLoadAvg()
+		if nil != err {
+			t.Fatal(err)
+		}
+		if load.Load1 != 0.50 {
+			t.Errorf("unexpected load: %+v", load)
+		}
+		processes, err := replayed.GetProcesses()
+		if nil != err {
+			t.Fatal(err)
+		}
+		if len(processes) != 1 || processes[0].Name != "sh" {
+			t.Errorf("unexpected processes: %+v", processes)
+		}
+		cgroup, err := replayed.GetCgroup("/app.slice")
+		if nil != err {
+			t.Fatal(err)
+		}
+		if len(cgroup.Controllers) != 2 {
+			t.Errorf("unexpected cgroup: %+v", cgroup)
+		}
+	}
+	if replay, _ := NewReplay(archive); replay.Len() != 2 {
+		t.Errorf("expected 2 recordings, got %d", replay.Len())
+	}
+
+	// Groups are recorded below the cgroup2 mount, whose Root need not be "/".
+	fsys = fstest.MapFS{
+		"proc/stat":                        &fstest.MapFile{Data: []byte("cpu  1 2 3 4 5 6 7 8 0 0\nbtime 1700000000\n")},
+		"proc/self/mountinfo":              &fstest.MapFile{Data: []byte("42 28 0:38 /kubepods/pod1 /sys/fs/cgroup rw - cgroup2 cgroup2 rw\n")},
+		"proc/self/cgroup":                 &fstest.MapFile{Data: []byte("0::/kubepods/pod1/app\n")},
+		"proc/meminfo":                     &fstest.MapFile{Data: []byte("MemTotal: 8388608 kB\nMemAvailable: 6291456 kB\n")},
+		"sys/devices/system/cpu/online":    &fstest.MapFile{Data: []byte("0-7\n")},
+		"sys/fs/cgroup/cgroup.controllers": &fstest.MapFile{Data: []byte("cpu memory\n")},
+		"sys/fs/cgroup/memory.max":         &fstest.MapFile{Data: []byte("1073741824\n")},
+		"sys/fs/cgroup/app/cpu.max":        &fstest.MapFile{Data: []byte("150000 100000\n")},
+	}
+	buffer.Reset()
+	writer = NewTarRecordWriter(buffer)
+	if err := NewRecorder(NewCollector(fsys, "proc", "sys"), writer).Record(at); nil != err {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); nil != err {
+		t.Fatal(err)
+	}
+	if archive, err = ReadTar(buffer); nil != err {
+		t.Fatal(err)
+	}
+	replay, err := NewReplay(archive)
+	if nil != err {
+		t.Fatal(err)
+	}
+	limits, err := replay.Collector(0).GetEffectiveLimits()
+	if nil != err {
+		t.Fatal(err)
+	}
+	if limits.CPUs != 1.5 || limits.MemoryLimit != 1<<30 {
+		t.Errorf("unexpected replayed limits: %+v", limits)
+	}
+}